@@ -0,0 +1,212 @@
+// Package accesslog implements structured access logging of the kernel requests. Logger interface is compatible
+// with the `*slog.Logger` (package `log/slog`), so any slog handler can be used:
+//
+//	kernel.Use(accesslog.New(slog.Default()).Middleware)
+package accesslog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// DefaultMessage is the default log record message.
+const DefaultMessage = "jsonrpc call"
+
+// Redacted replaces the values of the redacted fields.
+const Redacted = "[REDACTED]"
+
+// Logger is a structured logger (`*slog.Logger` implements it). Args are key-value pairs.
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// AccessLog writes a log record for each request: method, request ID, session ID, authenticated principal (ID and
+// authenticator, see auth.Capture), batch index and size, duration, error code and message, params and result sizes
+// (in bytes), and (optionally) params and result. Requests with server errors (internal and implementation-defined
+// server errors) are logged with the error level, except timeouts, authentication, access and rate limiting errors,
+// which are logged with the warning level.
+type AccessLog struct {
+	logger Logger
+	json   jsoniter.API
+
+	// Message is the log records message (DefaultMessage by default).
+	Message string
+
+	// LogParams enables request params logging.
+	LogParams bool
+
+	// LogResults enables response results logging.
+	LogResults bool
+
+	// RedactFields contains the names of the params and results object fields (case-insensitive, on any nesting
+	// level), which values should be replaced with Redacted (e.g. "password", "token").
+	RedactFields []string
+}
+
+// New creates new access log.
+func New(logger Logger) *AccessLog {
+	return &AccessLog{
+		logger:  logger,
+		json:    jsoniter.ConfigFastest,
+		Message: DefaultMessage,
+	}
+}
+
+// Middleware is a kernel middleware, that writes access log records.
+func (a *AccessLog) Middleware(next rpcKernel.RequestHandler) rpcKernel.RequestHandler {
+	return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+		var startedAt = time.Now()
+
+		ctx, principal := auth.Capture(ctx)
+
+		response := next(ctx, request)
+
+		args := []interface{}{"method", request.Method, "duration", time.Since(startedAt)}
+
+		if request.ID != nil {
+			args = append(args, "id", request.ID)
+		} else {
+			args = append(args, "notification", true)
+		}
+
+		if s, ok := session.FromContext(ctx); ok {
+			args = append(args, "session", s.ID())
+		}
+
+		if p, ok := principal(); ok {
+			args = append(args, "principal", p.ID)
+
+			if p.Authenticator != "" {
+				args = append(args, "authenticator", p.Authenticator)
+			}
+		}
+
+		if index, size, ok := rpcKernel.BatchFromContext(ctx); ok {
+			args = append(args, "batch_index", index, "batch_size", size)
+		}
+
+		args = append(args, a.payloadArgs("params", request.Params, a.LogParams)...)
+
+		if response == nil {
+			a.logger.InfoContext(ctx, a.Message, args...)
+
+			return response
+		}
+
+		if response.Error != nil {
+			args = append(args, "error_code", int(response.Error.Code), "error_message", response.Error.Message)
+
+			switch code := response.Error.Code; {
+			case isExpectedError(code):
+				a.logger.WarnContext(ctx, a.Message, args...)
+
+				return response
+
+			case isServerError(code):
+				a.logger.ErrorContext(ctx, a.Message, args...)
+
+				return response
+			}
+		} else {
+			var result interface{}
+
+			if r, ok := response.Result.(*interface{}); ok && r != nil {
+				result = *r
+			} else {
+				result = response.Result
+			}
+
+			args = append(args, a.payloadArgs("result", result, a.LogResults)...)
+		}
+
+		a.logger.InfoContext(ctx, a.Message, args...)
+
+		return response
+	}
+}
+
+// payloadArgs returns the payload size (and the payload, if needed) log args.
+func (a *AccessLog) payloadArgs(name string, payload interface{}, log bool) []interface{} {
+	if payload == nil {
+		return []interface{}{name + "_size", 0}
+	}
+
+	data, err := a.json.Marshal(payload)
+	if err != nil {
+		return []interface{}{name + "_size", 0}
+	}
+
+	args := []interface{}{name + "_size", len(data)}
+
+	if log {
+		var value interface{}
+
+		if err := a.json.Unmarshal(data, &value); err == nil {
+			args = append(args, name, a.redact(value))
+		}
+	}
+
+	return args
+}
+
+// redact replaces the values of RedactFields (recursively).
+func (a *AccessLog) redact(value interface{}) interface{} {
+	if len(a.RedactFields) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if a.isRedacted(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = a.redact(item)
+			}
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = a.redact(item)
+		}
+	}
+
+	return value
+}
+
+func (a *AccessLog) isRedacted(field string) bool {
+	for _, f := range a.RedactFields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExpectedError checks if the error code is the server error, that is not a server failure (timeout,
+// authentication, access or rate limiting error).
+func isExpectedError(code rpcErrors.Code) bool {
+	switch code {
+	case rpcErrors.Timeout, rpcErrors.Unauthorized, rpcErrors.Forbidden, rpcErrors.RateLimited:
+		return true
+	}
+
+	return false
+}
+
+// isServerError checks if the error code is internal or implementation-defined server error.
+func isServerError(code rpcErrors.Code) bool {
+	return code == rpcErrors.Internal || (code >= -32099 && code <= -32000) //nolint:gomnd
+}
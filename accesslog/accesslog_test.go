@@ -0,0 +1,224 @@
+package accesslog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type record struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+type fakeLogger struct {
+	mutex   sync.Mutex
+	records []record
+}
+
+func (l *fakeLogger) InfoContext(_ context.Context, msg string, args ...interface{}) {
+	l.log("info", msg, args)
+}
+
+func (l *fakeLogger) WarnContext(_ context.Context, msg string, args ...interface{}) {
+	l.log("warn", msg, args)
+}
+
+func (l *fakeLogger) ErrorContext(_ context.Context, msg string, args ...interface{}) {
+	l.log("error", msg, args)
+}
+
+func (l *fakeLogger) log(level, msg string, args []interface{}) {
+	attrs := make(map[string]interface{}, len(args)/2)
+
+	for i := 0; i < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+
+	delete(attrs, "duration") // not stable
+
+	l.mutex.Lock()
+	l.records = append(l.records, record{level: level, msg: msg, attrs: attrs})
+	l.mutex.Unlock()
+}
+
+type loginMethod struct{}
+
+func (*loginMethod) GetParamsType() interface{} { return nil }
+func (*loginMethod) GetName() string            { return "login" }
+func (*loginMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return map[string]interface{}{"user": "john", "Token": "secret"}, nil
+}
+
+type failingMethod struct{}
+
+func (*failingMethod) GetParamsType() interface{} { return nil }
+func (*failingMethod) GetName() string            { return "fail" }
+func (*failingMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, rpcErrors.New(rpcErrors.Internal)
+}
+
+type denyMethod struct{}
+
+func (*denyMethod) GetParamsType() interface{} { return nil }
+func (*denyMethod) GetName() string            { return "deny" }
+func (*denyMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, rpcErrors.New(rpcErrors.Forbidden)
+}
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(context.Context, string, interface{}) (*auth.Principal, error) {
+	return &auth.Principal{ID: "john", Authenticator: "apikey"}, nil
+}
+
+func newTestKernel(t *testing.T, configure func(a *AccessLog)) (*rpcKernel.Kernel, *fakeLogger) {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethods(&loginMethod{}, &failingMethod{}))
+
+	var (
+		logger = &fakeLogger{}
+		a      = New(logger)
+		kernel = rpcKernel.New(router)
+	)
+
+	if configure != nil {
+		configure(a)
+	}
+
+	kernel.Use(a.Middleware)
+
+	return kernel, logger
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		giveConfigure func(a *AccessLog)
+		giveJSON      string
+		wantRecord    record
+	}{
+		{
+			name:     "success",
+			giveJSON: `{"jsonrpc": "2.0", "method": "login", "params": {"password": "123"}, "id": 1}`,
+			wantRecord: record{level: "info", msg: DefaultMessage, attrs: map[string]interface{}{
+				"method": "login", "id": 1, "params_size": 18, "result_size": 32,
+			}},
+		},
+		{
+			name: "with params and result redaction",
+			giveConfigure: func(a *AccessLog) {
+				a.Message = "rpc"
+				a.LogParams, a.LogResults = true, true
+				a.RedactFields = []string{"Password", "token"}
+			},
+			giveJSON: `{"jsonrpc": "2.0", "method": "login", "params": [{"password": "123", "login": "john"}], "id": "a"}`,
+			wantRecord: record{level: "info", msg: "rpc", attrs: map[string]interface{}{
+				"method": "login", "id": "a", "params_size": 35, "result_size": 32,
+				"params": []interface{}{map[string]interface{}{"password": Redacted, "login": "john"}},
+				"result": map[string]interface{}{"user": "john", "Token": Redacted},
+			}},
+		},
+		{
+			name:     "notification",
+			giveJSON: `{"jsonrpc": "2.0", "method": "login"}`,
+			wantRecord: record{level: "info", msg: DefaultMessage, attrs: map[string]interface{}{
+				"method": "login", "notification": true, "params_size": 0, "result_size": 32,
+			}},
+		},
+		{
+			name:     "client error",
+			giveJSON: `{"jsonrpc": "2.0", "method": "foo", "id": 1}`,
+			wantRecord: record{level: "info", msg: DefaultMessage, attrs: map[string]interface{}{
+				"method": "foo", "id": 1, "params_size": 0,
+				"error_code": -32601, "error_message": "Method not found",
+			}},
+		},
+		{
+			name:     "server error",
+			giveJSON: `[{"jsonrpc": "2.0", "method": "fail", "id": 1}]`,
+			wantRecord: record{level: "error", msg: DefaultMessage, attrs: map[string]interface{}{
+				"method": "fail", "id": 1, "params_size": 0, "batch_index": 0, "batch_size": 1,
+				"error_code": -32603, "error_message": "Internal error",
+			}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			kernel, logger := newTestKernel(t, tt.giveConfigure)
+
+			kernel.HandleJSONRequest([]byte(tt.giveJSON))
+
+			assert.Equal(t, []record{tt.wantRecord}, logger.records)
+		})
+	}
+}
+
+func TestAccessLogDuration(t *testing.T) {
+	t.Parallel()
+
+	var duration interface{}
+
+	kernel := rpcKernel.New(rpcRouter.New())
+	kernel.Use(New(loggerFunc(func(args []interface{}) {
+		for i := 0; i < len(args); i += 2 {
+			if args[i] == "duration" {
+				duration = args[i+1]
+			}
+		}
+	})).Middleware)
+
+	kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo", "id": 1}`))
+
+	assert.IsType(t, time.Duration(0), duration)
+}
+
+type loggerFunc func(args []interface{})
+
+func (f loggerFunc) InfoContext(_ context.Context, _ string, args ...interface{})  { f(args) }
+func (f loggerFunc) WarnContext(_ context.Context, _ string, args ...interface{})  { f(args) }
+func (f loggerFunc) ErrorContext(_ context.Context, _ string, args ...interface{}) { f(args) }
+
+func TestAccessLogPrincipalAndExpectedErrors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = rpcRouter.New()
+		logger = &fakeLogger{}
+		kernel = rpcKernel.New(router)
+	)
+
+	assert.NoError(t, router.RegisterMethods(&loginMethod{}, &denyMethod{}))
+	router.Use(auth.New(fakeAuthenticator{}).Middleware)
+	kernel.Use(New(logger).Middleware)
+
+	kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "deny", "id": 1}`))
+
+	// server errors, that are not server failures, are logged with the warning level
+	assert.Equal(t, []record{{level: "warn", msg: DefaultMessage, attrs: map[string]interface{}{
+		"method": "deny", "id": 1, "params_size": 0, "principal": "john", "authenticator": "apikey",
+		"error_code": -32003, "error_message": "Forbidden",
+	}}}, logger.records)
+
+	for _, code := range []rpcErrors.Code{
+		rpcErrors.Timeout, rpcErrors.Unauthorized, rpcErrors.Forbidden, rpcErrors.RateLimited,
+	} {
+		assert.True(t, isExpectedError(code), code)
+		assert.True(t, isServerError(code), code)
+	}
+
+	assert.False(t, isExpectedError(rpcErrors.Internal))
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// DefaultAPIKeyHeader is the default API key header name.
+const DefaultAPIKeyHeader = "X-Api-Key"
+
+// APIKeys authenticates calls using the static API keys, passed in the request header.
+type APIKeys struct {
+	keys map[[sha256.Size]byte]*Principal // keys are hashed, so lookup time does not depend on the key value
+
+	// Header is the API key header name (DefaultAPIKeyHeader by default).
+	Header string
+}
+
+// NewAPIKeys creates API keys authenticator. Keys map contains the principals by API keys.
+func NewAPIKeys(keys map[string]*Principal) *APIKeys {
+	hashed := make(map[[sha256.Size]byte]*Principal, len(keys))
+
+	for key, principal := range keys {
+		if principal == nil {
+			principal = &Principal{}
+		}
+
+		p := *principal
+		p.Authenticator = "apikey"
+
+		hashed[sha256.Sum256([]byte(key))] = &p
+	}
+
+	return &APIKeys{keys: hashed, Header: DefaultAPIKeyHeader}
+}
+
+// Authenticate implements Authenticator interface.
+func (a *APIKeys) Authenticate(ctx context.Context, _ string, _ interface{}) (*Principal, error) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	key := md.Get(a.Header)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	if principal, found := a.keys[sha256.Sum256([]byte(key))]; found {
+		p := *principal
+
+		return &p, nil
+	}
+
+	return nil, ErrInvalidCredentials
+}
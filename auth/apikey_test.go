@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	a := NewAPIKeys(map[string]*Principal{
+		"secret-1": {ID: "john", Roles: []string{"admin"}},
+		"secret-2": nil,
+	})
+
+	principal, err := a.Authenticate(withHeader("X-Api-Key", "secret-1"), "foo", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "john", Roles: []string{"admin"}, Authenticator: "apikey"}, principal)
+
+	principal.ID = "changed" // copy is returned
+
+	principal, err = a.Authenticate(withHeader("X-Api-Key", "secret-1"), "foo", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "john", principal.ID)
+
+	principal, err = a.Authenticate(withHeader("X-Api-Key", "secret-2"), "foo", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{Authenticator: "apikey"}, principal)
+
+	_, err = a.Authenticate(withHeader("X-Api-Key", "wrong"), "foo", nil)
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	_, err = a.Authenticate(withHeader("X-Foo", "secret-1"), "foo", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	_, err = a.Authenticate(context.Background(), "foo", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	a.Header = "X-Foo"
+
+	_, err = a.Authenticate(withHeader("X-Foo", "secret-1"), "foo", nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,177 @@
+// Package auth provides pluggable authenticators (static API keys, HMAC-signed requests, JWT bearer tokens and mTLS
+// client certificates) and the router middleware, that authenticates each call before the method invoking and
+// attaches the principal to the context:
+//
+//	router.Use(auth.New(auth.NewAPIKeys(keys), jwt).Middleware)
+//
+// Authenticators read the credentials from the call metadata (see package metadata), so the transport must attach it.
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+var (
+	// ErrNoCredentials is returned by the Authenticator, when the call has no credentials for it (so the next
+	// authenticator is used).
+	ErrNoCredentials = errors.New("auth: missing credentials")
+
+	// ErrInvalidCredentials is returned by the Authenticator, when the call credentials are invalid.
+	ErrInvalidCredentials = errors.New("auth: invalid credentials")
+)
+
+// Principal is an authenticated client identity.
+type Principal struct {
+	ID            string                 // client identifier (key ID, token subject, certificate common name)
+	Roles         []string               // client roles (optional)
+	Scopes        []string               // client scopes (optional)
+	Claims        map[string]interface{} // additional attributes (e.g. JWT claims)
+	Authenticator string                 // name of the authenticator, that authenticated the client
+}
+
+// Authenticator authenticates the call using its metadata (and the method name and params, if needed). It returns
+// ErrNoCredentials, when the call has no credentials for this authenticator.
+type Authenticator interface {
+	Authenticate(ctx context.Context, method string, params interface{}) (*Principal, error)
+}
+
+// Auth authenticates calls using the authenticators (in the order of passing). The first authenticator, that does
+// not return ErrNoCredentials, decides. Unauthenticated calls are rejected with the errors.Unauthorized error.
+type Auth struct {
+	authenticators []Authenticator
+
+	// PublicMethods contains the canonical names of methods without version, that can be called without
+	// credentials (aliases and all versions of the methods are public too). When the valid credentials are passed -
+	// the principal is attached to the context anyway.
+	PublicMethods []string
+}
+
+type (
+	principalCtxKey struct{}
+	captureCtxKey   struct{}
+)
+
+// capture holds the principal, attached to the context down the call chain (see Capture).
+type capture struct {
+	mutex     sync.Mutex
+	principal *Principal
+}
+
+// New creates new Auth.
+func New(authenticators ...Authenticator) *Auth {
+	return &Auth{authenticators: authenticators}
+}
+
+// Middleware is a router middleware, that authenticates calls.
+func (a *Auth) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		principal, err := a.Authenticate(ctx, methodName, params)
+
+		switch {
+		case err == nil:
+			ctx = NewContext(ctx, principal)
+
+		case errors.Is(err, ErrNoCredentials) && a.isPublic(ctx, methodName):
+
+		default:
+			return nil, unauthorized(err)
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+// Authenticate authenticates the call using the authenticators.
+func (a *Auth) Authenticate(ctx context.Context, method string, params interface{}) (*Principal, error) {
+	for _, authenticator := range a.authenticators {
+		principal, err := authenticator.Authenticate(ctx, method, params)
+
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		if err == nil && principal == nil {
+			err = ErrInvalidCredentials
+		}
+
+		return principal, err
+	}
+
+	return nil, ErrNoCredentials
+}
+
+// isPublic checks if the method is public. Canonical method name (without version) is used, when the router
+// resolves it.
+func (a *Auth) isPublic(ctx context.Context, method string) bool {
+	if name, resolved := rpcRouter.MethodNameFromContext(ctx); resolved {
+		method = name
+
+		if i := strings.Index(method, rpcRouter.VersionSeparator); i >= 0 {
+			method = method[:i]
+		}
+	}
+
+	for _, name := range a.PublicMethods {
+		if name == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewContext returns a context with attached principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	if c, ok := ctx.Value(captureCtxKey{}).(*capture); ok && principal != nil {
+		c.mutex.Lock()
+		c.principal = principal
+		c.mutex.Unlock()
+	}
+
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// Capture returns a context, that captures the principal, attached down the call chain (e.g. by the Middleware), and
+// the function, that returns it. It allows the kernel middlewares (e.g. access log), that are executed before the
+// router middlewares, to get the authenticated principal after the call.
+func Capture(ctx context.Context) (context.Context, func() (*Principal, bool)) {
+	c := &capture{}
+
+	if principal, ok := FromContext(ctx); ok {
+		c.principal = principal
+	}
+
+	return context.WithValue(ctx, captureCtxKey{}, c), func() (*Principal, bool) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		return c.principal, c.principal != nil
+	}
+}
+
+// FromContext returns the authenticated principal (ok is false for unauthenticated calls).
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(*Principal)
+
+	return principal, ok && principal != nil
+}
+
+// unauthorized converts authentication error into the RPC error. Details of invalid credentials are not disclosed.
+func unauthorized(err error) *rpcErrors.Error {
+	result := rpcErrors.New(rpcErrors.Unauthorized)
+
+	if errors.Is(err, ErrNoCredentials) {
+		result.Data = ErrNoCredentials.Error()
+	} else {
+		result.Data = ErrInvalidCredentials.Error()
+	}
+
+	return result
+}
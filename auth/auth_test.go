@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type whoamiMethod struct{}
+
+func (*whoamiMethod) GetParamsType() interface{} { return nil }
+func (*whoamiMethod) GetName() string            { return "whoami" }
+func (*whoamiMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+func (*whoamiMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	if principal, ok := FromContext(ctx); ok {
+		return principal.ID, nil
+	}
+
+	return "anonymous", nil
+}
+
+type fakeAuthenticator struct {
+	principal *Principal
+	err       error
+}
+
+func (a fakeAuthenticator) Authenticate(context.Context, string, interface{}) (*Principal, error) {
+	return a.principal, a.err
+}
+
+// withHeader returns a context with HTTP metadata, that contains the header.
+func withHeader(key, value string) context.Context {
+	header := make(http.Header)
+	header.Set(key, value)
+
+	return metadata.NewContext(context.Background(), &metadata.Metadata{Transport: metadata.TransportHTTP, Header: header})
+}
+
+func TestAuth_Middleware(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		giveAuth      []Authenticator
+		givePublic    []string
+		wantResult    interface{}
+		wantErrorData interface{}
+	}{
+		{
+			name:       "authenticated",
+			giveAuth:   []Authenticator{fakeAuthenticator{principal: &Principal{ID: "john"}}},
+			wantResult: "john",
+		},
+		{
+			name: "next authenticator is used without credentials",
+			giveAuth: []Authenticator{
+				fakeAuthenticator{err: ErrNoCredentials},
+				fakeAuthenticator{principal: &Principal{ID: "jane"}},
+			},
+			wantResult: "jane",
+		},
+		{
+			name: "first authenticator with credentials decides",
+			giveAuth: []Authenticator{
+				fakeAuthenticator{err: ErrInvalidCredentials},
+				fakeAuthenticator{principal: &Principal{ID: "jane"}},
+			},
+			wantErrorData: "auth: invalid credentials",
+		},
+		{
+			name:          "without authenticators",
+			wantErrorData: "auth: missing credentials",
+		},
+		{
+			name:          "without credentials",
+			giveAuth:      []Authenticator{fakeAuthenticator{err: ErrNoCredentials}},
+			wantErrorData: "auth: missing credentials",
+		},
+		{
+			name:          "details are not disclosed",
+			giveAuth:      []Authenticator{fakeAuthenticator{err: errors.New("key foo is revoked")}},
+			wantErrorData: "auth: invalid credentials",
+		},
+		{
+			name:          "nil principal",
+			giveAuth:      []Authenticator{fakeAuthenticator{}},
+			wantErrorData: "auth: invalid credentials",
+		},
+		{
+			name:       "public method without credentials",
+			giveAuth:   []Authenticator{fakeAuthenticator{err: ErrNoCredentials}},
+			givePublic: []string{"whoami"},
+			wantResult: "anonymous",
+		},
+		{
+			name:       "public method with credentials",
+			giveAuth:   []Authenticator{fakeAuthenticator{principal: &Principal{ID: "john"}}},
+			givePublic: []string{"whoami"},
+			wantResult: "john",
+		},
+		{
+			name:          "public method with invalid credentials",
+			giveAuth:      []Authenticator{fakeAuthenticator{err: ErrInvalidCredentials}},
+			givePublic:    []string{"whoami"},
+			wantErrorData: "auth: invalid credentials",
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := rpcRouter.New()
+			assert.NoError(t, router.RegisterMethod(&whoamiMethod{}))
+
+			a := New(tt.giveAuth...)
+			a.PublicMethods = tt.givePublic
+
+			router.Use(a.Middleware)
+
+			result, err := router.InvokeContext(context.Background(), "whoami", nil)
+
+			if tt.wantErrorData != nil {
+				assert.Nil(t, result)
+				assert.Equal(t, int(rpcErrors.Unauthorized), err.GetCode())
+				assert.Equal(t, tt.wantErrorData, err.GetData())
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.wantResult, result)
+			}
+		})
+	}
+}
+
+func TestAuth_MiddlewarePublicAliasesAndVersions(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+	assert.NoError(t, router.RegisterMethod(&whoamiMethod{}))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", &whoamiMethod{}))
+	assert.NoError(t, router.RegisterAlias("me", "whoami"))
+
+	a := New(fakeAuthenticator{err: ErrNoCredentials})
+	a.PublicMethods = []string{"whoami"}
+
+	router.Use(a.Middleware)
+
+	for _, name := range []string{"whoami", "whoami@v2", "me"} {
+		result, err := router.InvokeContext(context.Background(), name, nil)
+		assert.Nil(t, err, name)
+		assert.Equal(t, "anonymous", result, name)
+	}
+
+	// public methods are matched by the name without version
+	a.PublicMethods = []string{"whoami@v2"}
+
+	_, err := router.InvokeContext(context.Background(), "whoami@v2", nil)
+	assert.Equal(t, int(rpcErrors.Unauthorized), err.GetCode())
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = FromContext(NewContext(context.Background(), nil))
+	assert.False(t, ok)
+
+	principal, ok := FromContext(NewContext(context.Background(), &Principal{ID: "foo"}))
+	assert.True(t, ok)
+	assert.Equal(t, "foo", principal.ID)
+}
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	ctx, captured := Capture(context.Background())
+
+	_, ok := captured()
+	assert.False(t, ok)
+
+	_ = NewContext(ctx, nil)
+
+	_, ok = captured()
+	assert.False(t, ok)
+
+	router := rpcRouter.New()
+	assert.NoError(t, router.RegisterMethod(&whoamiMethod{}))
+	router.Use(New(fakeAuthenticator{principal: &Principal{ID: "john"}}).Middleware)
+
+	_, err := router.InvokeContext(ctx, "whoami", nil)
+	assert.Nil(t, err)
+
+	principal, ok := captured()
+	assert.True(t, ok)
+	assert.Equal(t, "john", principal.ID)
+
+	// principal, that is already attached to the context
+	_, captured = Capture(NewContext(context.Background(), &Principal{ID: "jane"}))
+
+	principal, ok = captured()
+	assert.True(t, ok)
+	assert.Equal(t, "jane", principal.ID)
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// HMAC signature headers.
+const (
+	SignatureKeyHeader       = "X-Signature-Key"
+	SignatureTimestampHeader = "X-Signature-Timestamp" // unix time in seconds
+	SignatureHeader          = "X-Signature"           // hex encoded signature
+)
+
+// HMAC signature fields of the request params MetaField (e.g. `"params": {"_meta": {"signature": "..."}, ...}`).
+const (
+	MetaField                   = "_meta"
+	MetaSignatureKeyField       = "signature_key"
+	MetaSignatureTimestampField = "signature_timestamp" // unix time in seconds
+	MetaSignatureField          = "signature"           // hex encoded signature
+)
+
+// DefaultMaxClockSkew is the default allowed difference between the signature timestamp and the server time.
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// HMACKey is a shared secret of the HMAC-signed requests.
+type HMACKey struct {
+	Secret    []byte
+	Principal *Principal // optional (principal ID is the key ID by default)
+}
+
+// HMAC authenticates calls, signed using HMAC-SHA256 (see Sign). Signature is computed over the timestamp, method
+// name and canonicalized params, so it is transport-agnostic. Signature is passed in the params MetaField (each batch
+// entry carries its own signature), or in the signature headers - headers are the same for all entries of a batch,
+// so they can be used for the single calls only. Signed calls can be replayed within the MaxClockSkew window.
+type HMAC struct {
+	keys map[string]HMACKey
+	now  func() time.Time
+
+	// MaxClockSkew is the maximal allowed difference between the signature timestamp and the server time
+	// (DefaultMaxClockSkew by default).
+	MaxClockSkew time.Duration
+}
+
+// NewHMAC creates HMAC authenticator. Keys map contains the keys by key IDs.
+func NewHMAC(keys map[string]HMACKey) *HMAC {
+	return &HMAC{keys: keys, now: time.Now, MaxClockSkew: DefaultMaxClockSkew}
+}
+
+// Authenticate implements Authenticator interface.
+func (a *HMAC) Authenticate(ctx context.Context, method string, params interface{}) (*Principal, error) {
+	keyID, rawTimestamp, rawSignature, ok := signatureFields(ctx, params)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	key, found := a.keys[keyID]
+	if !found {
+		return nil, ErrInvalidCredentials
+	}
+
+	unix, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	timestamp := time.Unix(unix, 0)
+
+	if skew := a.now().Sub(timestamp); skew > a.MaxClockSkew || skew < -a.MaxClockSkew {
+		return nil, ErrInvalidCredentials
+	}
+
+	signature, err := hex.DecodeString(rawSignature)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	expected, err := sign(key.Secret, timestamp, method, params)
+	if err != nil || !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidCredentials
+	}
+
+	principal := Principal{ID: keyID}
+
+	if key.Principal != nil {
+		principal = *key.Principal
+	}
+
+	principal.Authenticator = "hmac"
+
+	return &principal, nil
+}
+
+// signatureFields returns the signature fields from the params MetaField (when it contains the signature) or from
+// the metadata headers.
+func signatureFields(ctx context.Context, params interface{}) (keyID, timestamp, signature string, ok bool) {
+	if p, isMap := params.(map[string]interface{}); isMap {
+		if meta, isMeta := p[MetaField].(map[string]interface{}); isMeta {
+			if signature, _ = meta[MetaSignatureField].(string); signature != "" {
+				keyID, _ = meta[MetaSignatureKeyField].(string)
+
+				switch ts := meta[MetaSignatureTimestampField].(type) {
+				case string:
+					timestamp = ts
+				case float64:
+					timestamp = strconv.FormatInt(int64(ts), 10)
+				}
+
+				return keyID, timestamp, signature, true
+			}
+		}
+	}
+
+	md, found := metadata.FromContext(ctx)
+	if !found || md.Get(SignatureHeader) == "" {
+		return "", "", "", false
+	}
+
+	return md.Get(SignatureKeyHeader), md.Get(SignatureTimestampHeader), md.Get(SignatureHeader), true
+}
+
+// Sign returns hex encoded HMAC-SHA256 signature of the call (for the SignatureHeader or MetaSignatureField):
+// `<unix timestamp>\n<method name>\n<params JSON>`, where params JSON object keys are sorted, there are no
+// insignificant whitespaces, and the params MetaField is excluded.
+func Sign(secret []byte, timestamp time.Time, method string, params interface{}) (string, error) {
+	signature, err := sign(secret, timestamp, method, params)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(signature), nil
+}
+
+func sign(secret []byte, timestamp time.Time, method string, params interface{}) ([]byte, error) {
+	canonical, err := canonicalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "\n" + method + "\n"))
+	_, _ = mac.Write(canonical)
+
+	return mac.Sum(nil), nil
+}
+
+// canonicalJSON encodes the params into JSON with sorted object keys, without MetaField (params are decoded before,
+// so structures and maps with the same content are encoded in the same way).
+func canonicalJSON(value interface{}) ([]byte, error) {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	if p, ok := decoded.(map[string]interface{}); ok {
+		delete(p, MetaField)
+	}
+
+	return json.Marshal(decoded)
+}
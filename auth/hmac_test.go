@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func TestHMAC_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Unix(1600000000, 0)
+		secret = []byte("secret")
+		params = map[string]interface{}{"b": 1, "a": []interface{}{"x", true}}
+	)
+
+	signature, err := Sign(secret, now, "foo", params)
+	assert.NoError(t, err)
+
+	// structures and maps with the same content have the same signature
+	same, _ := Sign(secret, now, "foo", struct {
+		A []interface{} `json:"a"`
+		B float64       `json:"b"`
+	}{A: []interface{}{"x", true}, B: 1})
+	assert.Equal(t, signature, same)
+
+	a := NewHMAC(map[string]HMACKey{
+		"key-1": {Secret: secret},
+		"key-2": {Secret: secret, Principal: &Principal{ID: "service", Roles: []string{"admin"}}},
+	})
+	a.now = func() time.Time { return now.Add(time.Minute) }
+
+	signed := func(key, timestamp, signature string) context.Context {
+		return metadata.NewContext(context.Background(), &metadata.Metadata{Header: http.Header{
+			SignatureKeyHeader:       {key},
+			SignatureTimestampHeader: {timestamp},
+			SignatureHeader:          {signature},
+		}})
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	principal, err := a.Authenticate(signed("key-1", timestamp, signature), "foo", params)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "key-1", Authenticator: "hmac"}, principal)
+
+	principal, err = a.Authenticate(signed("key-2", timestamp, signature), "foo", params)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "service", Roles: []string{"admin"}, Authenticator: "hmac"}, principal)
+
+	for name, tt := range map[string]struct {
+		ctx    context.Context
+		method string
+	}{
+		"unknown key":     {signed("key-3", timestamp, signature), "foo"},
+		"wrong timestamp": {signed("key-1", "foo", signature), "foo"},
+		"other timestamp": {signed("key-1", strconv.FormatInt(now.Unix()+1, 10), signature), "foo"},
+		"wrong signature": {signed("key-1", timestamp, "zz"), "foo"},
+		"other method":    {signed("key-1", timestamp, signature), "bar"},
+	} {
+		_, err = a.Authenticate(tt.ctx, tt.method, params)
+		assert.Equal(t, ErrInvalidCredentials, err, name)
+	}
+
+	_, err = a.Authenticate(signed("key-1", timestamp, signature), "foo", map[string]interface{}{"b": 2})
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	_, err = a.Authenticate(context.Background(), "foo", params)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	_, err = a.Authenticate(withHeader("X-Foo", "bar"), "foo", params)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	// expired signature
+	a.now = func() time.Time { return now.Add(DefaultMaxClockSkew + time.Second) }
+
+	_, err = a.Authenticate(signed("key-1", timestamp, signature), "foo", params)
+	assert.Equal(t, ErrInvalidCredentials, err)
+}
+
+func TestHMAC_AuthenticateBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Now()
+		secret = []byte("secret")
+		router = rpcRouter.New()
+		a      = NewHMAC(map[string]HMACKey{"key-1": {Secret: secret}})
+	)
+
+	assert.NoError(t, router.RegisterMethod(&whoamiMethod{}))
+	router.Use(New(a).Middleware)
+
+	entry := func(id int, signedID int) string {
+		signature, err := Sign(secret, now, "whoami", map[string]interface{}{"id": signedID})
+		assert.NoError(t, err)
+
+		return `{"jsonrpc": "2.0", "method": "whoami", "id": ` + strconv.Itoa(id) + `, "params": {"id": ` +
+			strconv.Itoa(id) + `, "_meta": {"signature_key": "key-1", "signature_timestamp": ` +
+			strconv.FormatInt(now.Unix(), 10) + `, "signature": "` + signature + `"}}}`
+	}
+
+	// each batch entry carries its own signature
+	result := rpcKernel.New(router).HandleJSONRequest([]byte("[" + entry(1, 1) + "," + entry(2, 2) + "," +
+		entry(3, 4) + "]"))
+
+	var responses []struct {
+		ID     int         `json:"id"`
+		Result interface{} `json:"result"`
+		Error  *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+
+	assert.NoError(t, json.Unmarshal(result, &responses))
+	assert.Len(t, responses, 3)
+
+	for _, response := range responses {
+		if response.ID == 3 { // signature of the other params
+			assert.Equal(t, int(rpcErrors.Unauthorized), response.Error.Code)
+		} else {
+			assert.Nil(t, response.Error, response.ID)
+			assert.Equal(t, "key-1", response.Result, response.ID)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// JWT authenticates calls using JWT bearer tokens (`Authorization: Bearer <token>` header), verified with the local
+// keys. Supported algorithms: HS256, HS384, HS512 (`[]byte` keys), RS256, RS384, RS512 (`*rsa.PublicKey` keys) and
+// ES256, ES384, ES512 (`*ecdsa.PublicKey` keys).
+//
+// Token claims are mapped into the principal: "sub" - ID, "roles" - roles, "scope" (space-separated string) or "scp"
+// (array) - scopes. Expiration ("exp") and "not before" ("nbf") claims are validated, when present.
+type JWT struct {
+	keys map[string]interface{}
+	json jsoniter.API
+	now  func() time.Time
+
+	// Issuer (optional) is the expected "iss" claim value.
+	Issuer string
+
+	// Audience (optional) is the expected "aud" claim value.
+	Audience string
+
+	// Leeway is the allowed clock skew for the time claims validation.
+	Leeway time.Duration
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// NewJWT creates JWT authenticator. Keys map contains the keys by key IDs ("kid" token header), the key with empty
+// ID is used for the tokens without "kid".
+func NewJWT(keys map[string]interface{}) (*JWT, error) {
+	for _, key := range keys {
+		switch key.(type) {
+		case []byte, *rsa.PublicKey, *ecdsa.PublicKey:
+		default:
+			return nil, errors.New("auth: unsupported JWT key type")
+		}
+	}
+
+	return &JWT{keys: keys, json: jsoniter.ConfigFastest, now: time.Now}, nil
+}
+
+// Authenticate implements Authenticator interface.
+func (a *JWT) Authenticate(ctx context.Context, _ string, _ interface{}) (*Principal, error) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	authorization := md.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "bearer ") { //nolint:gomnd
+		return nil, ErrNoCredentials
+	}
+
+	claims, err := a.verify(strings.TrimSpace(authorization[7:]))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err = a.validate(claims); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	principal := &Principal{Claims: claims, Authenticator: "jwt"}
+	principal.ID, _ = claims["sub"].(string)
+	principal.Roles = stringsClaim(claims["roles"])
+
+	if scope, isString := claims["scope"].(string); isString {
+		principal.Scopes = strings.Fields(scope)
+	} else {
+		principal.Scopes = stringsClaim(claims["scp"])
+	}
+
+	return principal, nil
+}
+
+// verify verifies the token signature and returns the token claims.
+func (a *JWT) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:gomnd
+		return nil, errors.New("auth: malformed token")
+	}
+
+	var header jwtHeader
+
+	if err := a.decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	key, found := a.keys[header.KeyID]
+	if !found {
+		return nil, errors.New("auth: unknown key")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifySignature(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+
+	if err = a.decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validate validates time, issuer and audience claims.
+func (a *JWT) validate(claims map[string]interface{}) error {
+	now := a.now()
+
+	if exp, ok := claims["exp"].(float64); ok && !now.Before(time.Unix(int64(exp), 0).Add(a.Leeway)) {
+		return errors.New("auth: token is expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("auth: token is not valid yet")
+	}
+
+	if a.Issuer != "" && claims["iss"] != a.Issuer {
+		return errors.New("auth: wrong token issuer")
+	}
+
+	if a.Audience != "" && claims["aud"] != a.Audience && !contains(stringsClaim(claims["aud"]), a.Audience) {
+		return errors.New("auth: wrong token audience")
+	}
+
+	return nil
+}
+
+func (a *JWT) decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return a.json.Unmarshal(data, v)
+}
+
+// verifySignature verifies the signature using the algorithm. Algorithm must correspond to the key type.
+func verifySignature(algorithm string, key interface{}, signed, signature []byte) error {
+	if len(algorithm) != 5 { //nolint:gomnd
+		return errors.New("auth: unsupported algorithm")
+	}
+
+	var hash crypto.Hash
+
+	switch algorithm[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return errors.New("auth: unsupported algorithm")
+	}
+
+	var errWrongSignature = errors.New("auth: wrong signature")
+
+	switch k := key.(type) {
+	case []byte:
+		if algorithm[:2] == "HS" {
+			mac := hmac.New(hash.New, k)
+			_, _ = mac.Write(signed)
+
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				return errWrongSignature
+			}
+
+			return nil
+		}
+
+	case *rsa.PublicKey:
+		if algorithm[:2] == "RS" {
+			digest := hash.New()
+			_, _ = digest.Write(signed)
+
+			return rsa.VerifyPKCS1v15(k, hash, digest.Sum(nil), signature)
+		}
+
+	case *ecdsa.PublicKey:
+		if algorithm[:2] == "ES" {
+			size := (k.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+			if len(signature) != 2*size {
+				return errWrongSignature
+			}
+
+			digest := hash.New()
+			_, _ = digest.Write(signed)
+
+			r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+
+			if !ecdsa.Verify(k, digest.Sum(nil), r, s) {
+				return errWrongSignature
+			}
+
+			return nil
+		}
+	}
+
+	return errors.New("auth: algorithm does not match the key")
+}
+
+// stringsClaim converts array claim into the strings slice (non-string items are ignored).
+func stringsClaim(claim interface{}) []string {
+	items, ok := claim.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if s, isString := item.(string); isString {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signJWT creates the token, signed using HS256, RS256 or ES256 algorithm.
+func signJWT(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		assert.NoError(t, err)
+
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	var (
+		signed    = encode(header) + "." + encode(claims)
+		digest    = sha256.Sum256([]byte(signed))
+		signature []byte
+	)
+
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		_, _ = mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+
+	case *rsa.PrivateKey:
+		var err error
+
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.NoError(t, err)
+
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.NoError(t, err)
+
+		signature = make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rb):32], rb) // left-padded with zeros
+		copy(signature[64-len(sb):], sb)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestNewJWT(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewJWT(map[string]interface{}{"": []byte("secret")})
+	assert.NoError(t, err)
+
+	_, err = NewJWT(map[string]interface{}{"": "secret"})
+	assert.Error(t, err)
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	a, err := NewJWT(map[string]interface{}{"": []byte("secret"), "rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey})
+	assert.NoError(t, err)
+
+	var now = time.Unix(1600000000, 0)
+
+	a.now = func() time.Time { return now }
+	a.Issuer = "issuer"
+	a.Audience = "api"
+
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		result := map[string]interface{}{"sub": "john", "iss": "issuer", "aud": "api", "exp": now.Unix() + 60}
+
+		for key, value := range extra {
+			result[key] = value
+		}
+
+		return result
+	}
+
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+
+	for name, tt := range map[string]struct {
+		giveToken     string
+		wantPrincipal *Principal
+		wantErr       error
+	}{
+		"HS256": {
+			giveToken: signJWT(t, hs256, claims(map[string]interface{}{
+				"roles": []string{"admin"}, "scope": "users:read users:write",
+			}), []byte("secret")),
+			wantPrincipal: &Principal{
+				ID: "john", Roles: []string{"admin"}, Scopes: []string{"users:read", "users:write"}, Authenticator: "jwt",
+			},
+		},
+		"RS256": {
+			giveToken: signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa"}, claims(map[string]interface{}{
+				"scp": []string{"users:read"}, "aud": []string{"web", "api"},
+			}), rsaKey),
+			wantPrincipal: &Principal{ID: "john", Scopes: []string{"users:read"}, Authenticator: "jwt"},
+		},
+		"ES256": {
+			giveToken:     signJWT(t, map[string]interface{}{"alg": "ES256", "kid": "ec"}, claims(nil), ecKey),
+			wantPrincipal: &Principal{ID: "john", Authenticator: "jwt"},
+		},
+		"algorithm does not match the key": {
+			giveToken: signJWT(t, map[string]interface{}{"alg": "HS256", "kid": "rsa"}, claims(nil), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"none algorithm": {
+			giveToken: signJWT(t, map[string]interface{}{"alg": "none"}, claims(nil), nil),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"unknown key": {
+			giveToken: signJWT(t, map[string]interface{}{"alg": "HS256", "kid": "foo"}, claims(nil), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"wrong signature": {
+			giveToken: signJWT(t, hs256, claims(nil), []byte("wrong")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"expired": {
+			giveToken: signJWT(t, hs256, claims(map[string]interface{}{"exp": now.Unix()}), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"not valid yet": {
+			giveToken: signJWT(t, hs256, claims(map[string]interface{}{"nbf": now.Unix() + 1}), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"wrong issuer": {
+			giveToken: signJWT(t, hs256, claims(map[string]interface{}{"iss": "foo"}), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"wrong audience": {
+			giveToken: signJWT(t, hs256, claims(map[string]interface{}{"aud": []string{"web"}}), []byte("secret")),
+			wantErr:   ErrInvalidCredentials,
+		},
+		"malformed": {
+			giveToken: "foo.bar",
+			wantErr:   ErrInvalidCredentials,
+		},
+	} {
+		principal, err := a.Authenticate(withHeader("Authorization", "Bearer "+tt.giveToken), "foo", nil)
+
+		if tt.wantErr != nil {
+			assert.Equal(t, tt.wantErr, err, name)
+			assert.Nil(t, principal, name)
+
+			continue
+		}
+
+		if assert.NoError(t, err, name) {
+			principal.Claims = nil
+			assert.Equal(t, tt.wantPrincipal, principal, name)
+		}
+	}
+
+	_, err = a.Authenticate(withHeader("Authorization", "Basic Zm9vOmJhcg=="), "foo", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+}
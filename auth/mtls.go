@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// ClientCertificates authenticates calls using TLS client certificates (mTLS). Principal ID is the certificate
+// subject common name.
+type ClientCertificates struct {
+	roots *x509.CertPool
+
+	// Principals (optional) contains the principals by certificate common names. When it is set, only listed
+	// identities are authenticated.
+	Principals map[string]*Principal
+}
+
+// NewClientCertificates creates mTLS authenticator. When roots pool is nil, certificates must be verified by the
+// TLS server (e.g. using `tls.RequireAndVerifyClientCert` client auth type).
+func NewClientCertificates(roots *x509.CertPool) *ClientCertificates {
+	return &ClientCertificates{roots: roots}
+}
+
+// Authenticate implements Authenticator interface.
+func (a *ClientCertificates) Authenticate(ctx context.Context, _ string, _ interface{}) (*Principal, error) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok || md.TLS == nil || len(md.TLS.PeerCertificates) == 0 {
+		return nil, ErrNoCredentials
+	}
+
+	if !a.verified(md) {
+		return nil, ErrInvalidCredentials
+	}
+
+	name := md.PeerCertificate()
+
+	principal := Principal{ID: name}
+
+	if a.Principals != nil {
+		p, found := a.Principals[name]
+		if !found {
+			return nil, ErrInvalidCredentials
+		}
+
+		if p != nil {
+			principal = *p
+		}
+	}
+
+	principal.Authenticator = "mtls"
+
+	return &principal, nil
+}
+
+func (a *ClientCertificates) verified(md *metadata.Metadata) bool {
+	if a.roots == nil {
+		return len(md.TLS.VerifiedChains) > 0
+	}
+
+	var (
+		certificates  = md.TLS.PeerCertificates
+		intermediates = x509.NewCertPool()
+	)
+
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	return err == nil
+}
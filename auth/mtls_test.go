@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// newCertificate creates the certificate, signed by the parent (self-signed CA certificate, when parent is nil).
+func newCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey,
+) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return certificate, key
+}
+
+func TestClientCertificates_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ca, caKey   = newCertificate(t, "ca", nil, nil)
+		client, _   = newCertificate(t, "client", ca, caKey)
+		stranger, _ = newCertificate(t, "stranger", nil, nil)
+		roots       = x509.NewCertPool()
+	)
+
+	roots.AddCert(ca)
+
+	withTLS := func(state *tls.ConnectionState) context.Context {
+		return metadata.NewContext(context.Background(), &metadata.Metadata{TLS: state})
+	}
+
+	// verification using the roots pool
+	a := NewClientCertificates(roots)
+
+	principal, err := a.Authenticate(withTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{client}}), "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "client", Authenticator: "mtls"}, principal)
+
+	_, err = a.Authenticate(withTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{stranger}}), "", nil)
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	_, err = a.Authenticate(withTLS(&tls.ConnectionState{}), "", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	_, err = a.Authenticate(withTLS(nil), "", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	_, err = a.Authenticate(context.Background(), "", nil)
+	assert.Equal(t, ErrNoCredentials, err)
+
+	// allowed identities
+	a.Principals = map[string]*Principal{"client": {ID: "client-service", Roles: []string{"admin"}}}
+
+	principal, err = a.Authenticate(withTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{client}}), "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "client-service", Roles: []string{"admin"}, Authenticator: "mtls"}, principal)
+
+	a.Principals = map[string]*Principal{"foo": nil}
+
+	_, err = a.Authenticate(withTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{client}}), "", nil)
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	// verification by the TLS server
+	a = NewClientCertificates(nil)
+
+	_, err = a.Authenticate(withTLS(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{stranger}}), "", nil)
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	principal, err = a.Authenticate(withTLS(&tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{stranger},
+		VerifiedChains:   [][]*x509.Certificate{{stranger}},
+	}), "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "stranger", principal.ID)
+}
@@ -0,0 +1,276 @@
+// Package authz provides methods authorization using roles and scopes of the authenticated principal (see package
+// auth). Requirements are declared by the methods (see RolesRequirer and ScopesRequirer interfaces) or by the policy
+// rules with wildcards on the method namespaces:
+//
+//	policy := authz.New()
+//	_ = policy.Require("billing.**", authz.Requirement{Roles: []string{"accountant"}})
+//	router.Use(authentication.Middleware, policy.Middleware)
+//
+// Authorization is a router middleware, so each batch entry is authorized separately.
+package authz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// Rules patterns wildcards have the same meaning, as in the router fallback patterns (see router.MatchPattern).
+const (
+	// Wildcard matches exactly one method name segment (e.g. `user.*` matches `user.get`, but not `user.role.get`).
+	Wildcard = rpcRouter.Wildcard
+
+	// MultiWildcard matches one or more trailing method name segments, it is allowed as the last pattern segment
+	// only (e.g. `user.**` matches `user.get` and `user.role.get`).
+	MultiWildcard = rpcRouter.MultiWildcard
+)
+
+var (
+	// ErrUnauthenticated is returned, when the method requires authentication, but the call is not authenticated.
+	ErrUnauthenticated = errors.New("authz: authentication required")
+
+	// ErrForbidden is returned, when the principal is not allowed to call the method.
+	ErrForbidden = errors.New("authz: access denied")
+)
+
+type (
+	// RolesRequirer is an optional jsonrpc.Method interface, that allows method to declare the roles, one of which
+	// the principal must have.
+	RolesRequirer interface {
+		RequiredRoles() []string
+	}
+
+	// ScopesRequirer is an optional jsonrpc.Method interface, that allows method to declare the scopes, all of which
+	// the principal must have.
+	ScopesRequirer interface {
+		RequiredScopes() []string
+	}
+)
+
+// Requirement is a method access requirement. Requirement without roles and scopes requires authentication only.
+type Requirement struct {
+	Roles  []string // principal must have at least one of the roles (when set)
+	Scopes []string // principal must have all the scopes (when set)
+	Public bool     // method can be called without authentication (roles and scopes are ignored)
+}
+
+type rule struct {
+	segments    []string
+	requirement Requirement
+}
+
+// Policy authorizes the calls using the rules and the requirements, declared by the methods (both must be met).
+// Rules patterns are matched against the canonical names of the resolved methods (see router.MethodNameFromContext,
+// aliases and versions are resolved, and the version suffix is not matched), or the called names for the unknown
+// methods. The most specific rule is applied (the rule with more non-wildcard segments, then - with more segments,
+// then - the rule without MultiWildcard).
+type Policy struct {
+	mutex sync.RWMutex
+	rules []rule
+
+	// DenyUnmatched denies calls of the methods without matched rules and declared requirements (by default they
+	// are allowed).
+	DenyUnmatched bool
+}
+
+// New creates new authorization policy.
+func New() *Policy {
+	return &Policy{rules: make([]rule, 0)}
+}
+
+// Require adds (or replaces) the rule for the methods pattern. Pattern is a method name (e.g. `user.get`), where
+// any segment can be a Wildcard (`user.*`, `*.get`, `*`), and the last one - MultiWildcard (`user.**`, `**`).
+func (p *Policy) Require(pattern string, requirement Requirement) error {
+	segments := strings.Split(pattern, rpcRouter.Separator)
+
+	for i, segment := range segments {
+		switch {
+		case segment == Wildcard, segment == MultiWildcard && i == len(segments)-1:
+		case segment == "" || strings.Contains(segment, Wildcard):
+			return errors.New("authz: wrong pattern " + pattern)
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i, r := range p.rules {
+		if strings.Join(r.segments, rpcRouter.Separator) == pattern {
+			p.rules[i].requirement = requirement
+
+			return nil
+		}
+	}
+
+	p.rules = append(p.rules, rule{segments: segments, requirement: requirement})
+
+	return nil
+}
+
+// Middleware is a router middleware, that authorizes calls. Unauthenticated calls are rejected with the
+// errors.Unauthorized error, and forbidden - with the errors.Forbidden error.
+func (p *Policy) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		if err := p.Authorize(ctx, methodName); err != nil {
+			if errors.Is(err, ErrUnauthenticated) {
+				rpcErr := rpcErrors.New(rpcErrors.Unauthorized)
+				rpcErr.Data = err.Error()
+
+				return nil, rpcErr
+			}
+
+			return nil, rpcErrors.New(rpcErrors.Forbidden)
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+// Authorize checks if the call is allowed. Method, that is going to be invoked, and its canonical name are taken
+// from the context (see router.MethodFromContext and router.MethodNameFromContext).
+func (p *Policy) Authorize(ctx context.Context, methodName string) error {
+	var (
+		principal, authenticated = auth.FromContext(ctx)
+		requirements             = make([]Requirement, 0, 2) //nolint:gomnd
+	)
+
+	if name, resolved := rpcRouter.MethodNameFromContext(ctx); resolved {
+		methodName = name
+	}
+
+	if i := strings.Index(methodName, rpcRouter.VersionSeparator); i >= 0 {
+		methodName = methodName[:i]
+	}
+
+	if requirement, found := p.match(methodName); found {
+		requirements = append(requirements, requirement)
+	}
+
+	if method, found := rpcRouter.MethodFromContext(ctx); found {
+		if requirement, declared := declaredRequirement(method); declared {
+			requirements = append(requirements, requirement)
+		}
+	}
+
+	if len(requirements) == 0 && p.DenyUnmatched {
+		return ErrForbidden
+	}
+
+	for _, requirement := range requirements {
+		if requirement.Public {
+			continue
+		}
+
+		if !authenticated {
+			return ErrUnauthenticated
+		}
+
+		if !requirement.allows(principal) {
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// match returns the requirement of the most specific rule, matched with the method name.
+func (p *Policy) match(methodName string) (result Requirement, found bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var (
+		segments               = strings.Split(methodName, rpcRouter.Separator)
+		bestLiterals, bestSize = -1, -1
+		bestMulti              bool
+	)
+
+	for _, r := range p.rules {
+		literals, ok := matchSegments(r.segments, segments)
+		if !ok {
+			continue
+		}
+
+		multi := r.segments[len(r.segments)-1] == MultiWildcard
+
+		if literals > bestLiterals ||
+			(literals == bestLiterals && len(r.segments) > bestSize) ||
+			(literals == bestLiterals && len(r.segments) == bestSize && bestMulti && !multi) {
+			result, found = r.requirement, true
+			bestLiterals, bestSize, bestMulti = literals, len(r.segments), multi
+		}
+	}
+
+	return
+}
+
+// matchSegments matches the method name segments with the pattern and returns the number of matched non-wildcard
+// pattern segments.
+func matchSegments(pattern, segments []string) (literals int, ok bool) {
+	for i, segment := range pattern {
+		if i >= len(segments) {
+			return 0, false
+		}
+
+		if segment == MultiWildcard { // it is always the last pattern segment
+			return literals, true
+		}
+
+		if segment == Wildcard {
+			continue
+		}
+
+		if segment != segments[i] {
+			return 0, false
+		}
+
+		literals++
+	}
+
+	return literals, len(pattern) == len(segments)
+}
+
+// declaredRequirement returns the requirement, declared by the method.
+func declaredRequirement(method jsonrpc.Method) (result Requirement, declared bool) {
+	if m, ok := method.(RolesRequirer); ok {
+		result.Roles, declared = m.RequiredRoles(), true
+	}
+
+	if m, ok := method.(ScopesRequirer); ok {
+		result.Scopes, declared = m.RequiredScopes(), true
+	}
+
+	return
+}
+
+// allows checks if the principal meets the requirement.
+func (requirement Requirement) allows(principal *auth.Principal) bool {
+	if len(requirement.Roles) > 0 && !containsAny(principal.Roles, requirement.Roles) {
+		return false
+	}
+
+	for _, scope := range requirement.Scopes {
+		if !containsAny(principal.Scopes, []string{scope}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAny(items, values []string) bool {
+	for _, item := range items {
+		for _, value := range values {
+			if item == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
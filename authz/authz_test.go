@@ -0,0 +1,208 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type okMethod struct{ name string }
+
+func (*okMethod) GetParamsType() interface{}                        { return nil }
+func (m *okMethod) GetName() string                                 { return m.name }
+func (*okMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return "ok", nil }
+
+type protectedMethod struct {
+	okMethod
+	roles, scopes []string
+}
+
+func (m *protectedMethod) RequiredRoles() []string  { return m.roles }
+func (m *protectedMethod) RequiredScopes() []string { return m.scopes }
+
+func newTestRouter(t *testing.T, policy *Policy) *rpcRouter.Router {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethods(
+		&okMethod{name: "user.get"},
+		&okMethod{name: "user.delete"},
+		&okMethod{name: "billing.invoice.create"},
+		&okMethod{name: "billing.invoice.get"},
+		&okMethod{name: "status"},
+		&protectedMethod{okMethod: okMethod{name: "admin.reset"}, roles: []string{"admin"}, scopes: []string{"reset"}},
+	))
+
+	assert.NoError(t, router.RegisterAlias("legacy.reset", "admin.reset"))
+	assert.NoError(t, router.RegisterAlias("invoice.create", "billing.invoice.create"))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", &okMethod{name: "billing.invoice.create"}))
+
+	router.Use(policy.Middleware)
+
+	return router
+}
+
+func TestPolicy_Require(t *testing.T) {
+	t.Parallel()
+
+	policy := New()
+
+	for _, pattern := range []string{"user.get", "user.*", "*.get", "*", "billing.*.create", "user.**", "**", "*.**"} {
+		assert.NoError(t, policy.Require(pattern, Requirement{}), pattern)
+	}
+
+	for _, pattern := range []string{"", "user.", ".get", "user*", "user.g*t", "user..get", "**.get", "user.***"} {
+		assert.Error(t, policy.Require(pattern, Requirement{}), pattern)
+	}
+
+	// rule replacing
+	assert.NoError(t, policy.Require("user.get", Requirement{Public: true}))
+
+	requirement, found := policy.match("user.get")
+	assert.True(t, found)
+	assert.True(t, requirement.Public)
+}
+
+func TestPolicy_match(t *testing.T) {
+	t.Parallel()
+
+	policy := New()
+
+	assert.NoError(t, policy.Require("user.*", Requirement{Roles: []string{"one"}}))
+	assert.NoError(t, policy.Require("user.**", Requirement{Roles: []string{"many"}}))
+
+	for name, want := range map[string][]string{
+		"user.get":      {"one"}, // the rule without multi-segment wildcard is more specific
+		"user.role.get": {"many"},
+	} {
+		requirement, found := policy.match(name)
+		assert.True(t, found, name)
+		assert.Equal(t, want, requirement.Roles, name)
+	}
+
+	_, found := policy.match("user")
+	assert.False(t, found)
+}
+
+func TestPolicy_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		admin   = &auth.Principal{ID: "admin", Roles: []string{"admin"}, Scopes: []string{"reset", "users:write"}}
+		support = &auth.Principal{ID: "support", Roles: []string{"support"}, Scopes: []string{"users:write"}}
+		nobody  = &auth.Principal{ID: "nobody"}
+	)
+
+	policy := New()
+
+	assert.NoError(t, policy.Require("**", Requirement{}))
+	assert.NoError(t, policy.Require("status", Requirement{Public: true}))
+	assert.NoError(t, policy.Require("user.*", Requirement{Roles: []string{"admin", "support"}}))
+	assert.NoError(t, policy.Require("user.delete", Requirement{Scopes: []string{"users:write"}}))
+	assert.NoError(t, policy.Require("billing.**", Requirement{Roles: []string{"accountant"}}))
+	assert.NoError(t, policy.Require("billing.*.get", Requirement{}))
+
+	router := newTestRouter(t, policy)
+
+	for _, tt := range []struct {
+		principal *auth.Principal
+		method    string
+		wantCode  rpcErrors.Code
+	}{
+		{principal: nil, method: "status"},
+		{principal: nil, method: "user.get", wantCode: rpcErrors.Unauthorized},
+		{principal: nil, method: "unknown", wantCode: rpcErrors.Unauthorized},
+		{principal: nobody, method: "unknown", wantCode: rpcErrors.MethodNotFound},
+		{principal: admin, method: "user.get"},
+		{principal: support, method: "user.get"},
+		{principal: nobody, method: "user.get", wantCode: rpcErrors.Forbidden},
+		{principal: support, method: "user.delete"},
+		{principal: admin, method: "user.delete"},
+		{principal: admin, method: "billing.invoice.create", wantCode: rpcErrors.Forbidden},
+		{principal: nobody, method: "billing.invoice.get"},
+		{principal: admin, method: "admin.reset"},
+		{principal: support, method: "admin.reset", wantCode: rpcErrors.Forbidden},
+		{principal: support, method: "legacy.reset", wantCode: rpcErrors.Forbidden}, // method requirements
+		{principal: &auth.Principal{Roles: []string{"admin"}}, method: "admin.reset", wantCode: rpcErrors.Forbidden},
+		// aliases and versions cannot bypass the namespace rules
+		{principal: admin, method: "invoice.create", wantCode: rpcErrors.Forbidden},
+		{principal: admin, method: "billing.invoice.create@v2", wantCode: rpcErrors.Forbidden},
+		{principal: &auth.Principal{Roles: []string{"accountant"}}, method: "invoice.create"},
+	} {
+		ctx := context.Background()
+
+		if tt.principal != nil {
+			ctx = auth.NewContext(ctx, tt.principal)
+		}
+
+		result, err := router.InvokeContext(ctx, tt.method, nil)
+
+		if tt.wantCode == 0 {
+			assert.Nil(t, err, tt.method)
+			assert.Equal(t, "ok", result, tt.method)
+		} else if assert.NotNil(t, err, tt.method) {
+			assert.Equal(t, int(tt.wantCode), err.GetCode(), tt.method)
+		}
+	}
+}
+
+func TestPolicy_DenyUnmatched(t *testing.T) {
+	t.Parallel()
+
+	policy := New()
+	policy.DenyUnmatched = true
+
+	assert.NoError(t, policy.Require("user.get", Requirement{Public: true}))
+
+	var (
+		router = newTestRouter(t, policy)
+		ctx    = auth.NewContext(context.Background(), &auth.Principal{ID: "admin", Roles: []string{"admin"}})
+	)
+
+	_, err := router.InvokeContext(ctx, "user.get", nil)
+	assert.Nil(t, err)
+
+	_, err = router.InvokeContext(ctx, "user.delete", nil)
+	assert.Equal(t, int(rpcErrors.Forbidden), err.GetCode())
+
+	_, err = router.InvokeContext(ctx, "admin.reset", nil) // scopes are missing
+	assert.Equal(t, int(rpcErrors.Forbidden), err.GetCode())
+}
+
+func TestPolicy_Batch(t *testing.T) {
+	t.Parallel()
+
+	policy := New()
+
+	assert.NoError(t, policy.Require("user.delete", Requirement{Roles: []string{"admin"}}))
+
+	var (
+		kernel = rpcKernel.New(newTestRouter(t, policy))
+		ctx    = auth.NewContext(context.Background(), &auth.Principal{ID: "support", Roles: []string{"support"}})
+	)
+
+	result := kernel.HandleJSONRequestContext(ctx, []byte(`[
+		{"jsonrpc": "2.0", "method": "user.delete", "id": 1},
+		{"jsonrpc": "2.0", "method": "user.get", "id": 2}
+	]`))
+
+	var responses []map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(result, &responses))
+	assert.Len(t, responses, 2)
+
+	for _, response := range responses {
+		switch response["id"] {
+		case float64(1):
+			assert.Equal(t, map[string]interface{}{"code": float64(-32003), "message": "Forbidden"}, response["error"])
+		case float64(2):
+			assert.Equal(t, "ok", response["result"])
+		}
+	}
+}
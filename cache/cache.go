@@ -0,0 +1,337 @@
+// Package cache provides results caching for the pure methods. Methods opt into caching by implementing CachedMethod
+// interface or using Cache.SetTTL, and results are cached by the canonical method name (aliases and versions are
+// resolved, see router.MethodNameFromContext), the authenticated principal (see auth.FromContext) and canonicalized
+// params:
+//
+//	c := cache.New(cache.NewLRUStore(cache.DefaultCapacity))
+//	c.Recorder = registry // *metrics.Registry, for hits and misses metrics
+//	router.Use(c.Middleware)
+//
+// Results are cached per principal, so the middleware must be used after the authentication (calls without
+// principal share the results). Results of the methods, that return the same result for all clients, can be shared
+// between principals using PublicCachedMethod interface or Cache.SetPublic.
+//
+// Handlers can invalidate cached results using the cache from the context (see FromContext).
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// metaField is a reserved request params field for the calls metadata (e.g. trace context), it is not a part of
+// the cache key.
+const metaField = "_meta"
+
+type (
+	// CachedMethod is an optional jsonrpc.Method interface, that allows method to opt into results caching.
+	CachedMethod interface {
+		CacheTTL() time.Duration
+	}
+
+	// PublicCachedMethod is an optional jsonrpc.Method interface, that allows method to share cached results between
+	// principals (the method result must not depend on the client).
+	PublicCachedMethod interface {
+		CachePublic() bool
+	}
+
+	// Recorder records cache hits and misses (*metrics.Registry implements it).
+	Recorder interface {
+		CacheHit(method string)
+		CacheMiss(method string)
+	}
+)
+
+// Cache caches successful methods results. Cached results are decoded from JSON (so structures are returned as
+// maps). Store errors are not reported (method is invoked as without caching).
+type Cache struct {
+	store Store
+	json  jsoniter.API
+
+	mutex       sync.RWMutex
+	ttls        map[string]time.Duration
+	public      map[string]bool
+	generations map[string]uint64              // incremented on the method invalidation, it is a part of the key
+	versions    map[string]map[string]struct{} // cached versions of the methods
+	principals  map[string]map[string]struct{} // principals with the cached results of the methods
+
+	// Recorder (optional) records cache hits and misses.
+	Recorder Recorder
+}
+
+type cacheCtxKey struct{}
+
+// New creates new results cache.
+func New(store Store) *Cache {
+	return &Cache{
+		store:       store,
+		json:        jsoniter.ConfigCompatibleWithStandardLibrary, // sorted map keys are required for cache keys
+		ttls:        make(map[string]time.Duration),
+		public:      make(map[string]bool),
+		generations: make(map[string]uint64),
+		versions:    make(map[string]map[string]struct{}),
+		principals:  make(map[string]map[string]struct{}),
+	}
+}
+
+// SetTTL sets the method results TTL (it overrides CachedMethod TTL). Zero TTL disables caching for the method.
+// Method name without version sets the TTL for all method versions (unless the version TTL is set).
+func (c *Cache) SetTTL(method string, ttl time.Duration) {
+	c.mutex.Lock()
+	c.ttls[method] = ttl
+	c.mutex.Unlock()
+}
+
+// SetPublic sets whether the method results are shared between principals (it overrides PublicCachedMethod). Method
+// name without version applies to all method versions (unless it is set for the version).
+func (c *Cache) SetPublic(method string, public bool) {
+	c.mutex.Lock()
+	c.public[method] = public
+	c.mutex.Unlock()
+}
+
+// Middleware is a router middleware, that caches registered methods results (calls of the unknown methods, e.g.
+// handled by the router fallback, are not cached). Cache is attached to the context of all calls.
+func (c *Cache) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		ctx = context.WithValue(ctx, cacheCtxKey{}, c)
+
+		name, resolved := rpcRouter.MethodNameFromContext(ctx)
+		if !resolved {
+			return next(ctx, methodName, params) // only registered methods are cached
+		}
+
+		ttl := c.ttl(ctx, name)
+		if ttl <= 0 {
+			return next(ctx, methodName, params)
+		}
+
+		principal := c.principal(ctx, name)
+
+		key, err := c.key(name, principal, params)
+		if err != nil {
+			return next(ctx, methodName, params)
+		}
+
+		if data, found, _ := c.store.Get(key); found {
+			var result interface{}
+
+			if c.json.Unmarshal(data, &result) == nil {
+				if c.Recorder != nil {
+					c.Recorder.CacheHit(name)
+				}
+
+				return result, nil
+			}
+		}
+
+		if c.Recorder != nil {
+			c.Recorder.CacheMiss(name)
+		}
+
+		result, rpcErr := next(ctx, methodName, params)
+
+		if rpcErr == nil {
+			if data, marshalErr := c.json.Marshal(result); marshalErr == nil {
+				c.remember(name, principal)
+				_ = c.store.Set(key, data, ttl)
+			}
+		}
+
+		return result, rpcErr
+	}
+}
+
+// Invalidate removes the cached method results of all principals for the params. Method is the canonical method name
+// (see router.Router.CanonicalName), and the name without version removes the results of all method versions.
+func (c *Cache) Invalidate(method string, params interface{}) error {
+	var (
+		base, _    = splitVersion(method)
+		names      = []string{method}
+		principals = []string{""}
+	)
+
+	c.mutex.RLock()
+	if !strings.Contains(method, rpcRouter.VersionSeparator) {
+		for version := range c.versions[method] {
+			names = append(names, method+rpcRouter.VersionSeparator+version)
+		}
+	}
+
+	for principal := range c.principals[base] {
+		principals = append(principals, principal)
+	}
+	c.mutex.RUnlock()
+
+	for _, name := range names {
+		for _, principal := range principals {
+			key, err := c.key(name, principal, params)
+			if err != nil {
+				return err
+			}
+
+			if err = c.store.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// InvalidateMethod invalidates all cached results of all method versions (they are not removed from the store, but
+// will never be returned, and will be evicted or expired). Method is the canonical method name (see
+// router.Router.CanonicalName). Invalidation is not shared between processes, that use the same store.
+func (c *Cache) InvalidateMethod(method string) {
+	base, _ := splitVersion(method)
+
+	c.mutex.Lock()
+	c.generations[base]++
+	c.mutex.Unlock()
+}
+
+// ttl returns the method results TTL.
+func (c *Cache) ttl(ctx context.Context, methodName string) time.Duration {
+	base, _ := splitVersion(methodName)
+
+	c.mutex.RLock()
+	ttl, ok := c.ttls[methodName]
+	if !ok {
+		ttl, ok = c.ttls[base]
+	}
+	c.mutex.RUnlock()
+
+	if ok {
+		return ttl
+	}
+
+	if method, found := rpcRouter.MethodFromContext(ctx); found {
+		if cached, isCached := method.(CachedMethod); isCached {
+			return cached.CacheTTL()
+		}
+	}
+
+	return 0
+}
+
+// principal returns the principal part of the cache key (empty for the public methods and anonymous calls).
+func (c *Cache) principal(ctx context.Context, methodName string) string {
+	base, _ := splitVersion(methodName)
+
+	c.mutex.RLock()
+	public, ok := c.public[methodName]
+	if !ok {
+		public, ok = c.public[base]
+	}
+	c.mutex.RUnlock()
+
+	if !ok {
+		if method, found := rpcRouter.MethodFromContext(ctx); found {
+			if p, isPublic := method.(PublicCachedMethod); isPublic {
+				public = p.CachePublic()
+			}
+		}
+	}
+
+	if public {
+		return ""
+	}
+
+	if principal, authenticated := auth.FromContext(ctx); authenticated {
+		return principal.Authenticator + ":" + principal.ID
+	}
+
+	return ""
+}
+
+// key returns the cache key: method name, its generation, principal and the hash of canonicalized params.
+func (c *Cache) key(method, principal string, params interface{}) (string, error) {
+	data, err := c.json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	var decoded interface{}
+
+	if err = c.json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+
+	if p, ok := decoded.(map[string]interface{}); ok {
+		delete(p, metaField)
+	}
+
+	if data, err = c.json.Marshal(decoded); err != nil {
+		return "", err
+	}
+
+	base, _ := splitVersion(method)
+
+	c.mutex.RLock()
+	generation := c.generations[base]
+	c.mutex.RUnlock()
+
+	sum := sha256.Sum256(data)
+
+	return method + "\x00" + strconv.FormatUint(generation, 10) + "\x00" + principal + "\x00" + hex.EncodeToString(sum[:]),
+		nil
+}
+
+// remember remembers the cached method version and principal (they are required for the invalidation by the name
+// without version and for all principals).
+func (c *Cache) remember(method, principal string) {
+	base, version := splitVersion(method)
+
+	c.mutex.RLock()
+	_, versionExists := c.versions[base][version]
+	_, principalExists := c.principals[base][principal]
+	c.mutex.RUnlock()
+
+	if (version == "" || versionExists) && (principal == "" || principalExists) {
+		return
+	}
+
+	c.mutex.Lock()
+	if version != "" {
+		if c.versions[base] == nil {
+			c.versions[base] = make(map[string]struct{})
+		}
+
+		c.versions[base][version] = struct{}{}
+	}
+
+	if principal != "" {
+		if c.principals[base] == nil {
+			c.principals[base] = make(map[string]struct{})
+		}
+
+		c.principals[base][principal] = struct{}{}
+	}
+	c.mutex.Unlock()
+}
+
+// splitVersion splits the method name into the name without version and the version.
+func splitVersion(method string) (base, version string) {
+	if i := strings.Index(method, rpcRouter.VersionSeparator); i >= 0 {
+		return method[:i], method[i+len(rpcRouter.VersionSeparator):]
+	}
+
+	return method, ""
+}
+
+// FromContext returns the cache, that is attached to the context by the Middleware.
+func FromContext(ctx context.Context) (*Cache, bool) {
+	c, ok := ctx.Value(cacheCtxKey{}).(*Cache)
+
+	return c, ok && c != nil
+}
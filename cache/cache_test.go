@@ -0,0 +1,285 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metrics"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+var _ Recorder = metrics.NewRegistry()
+
+type (
+	userGetMethod struct {
+		calls int32
+	}
+	userGetMethodParams struct {
+		ID int `json:"id"`
+	}
+)
+
+func (*userGetMethod) GetParamsType() interface{} { return &userGetMethodParams{} }
+func (*userGetMethod) GetName() string            { return "user.get" }
+func (*userGetMethod) CacheTTL() time.Duration    { return time.Minute }
+func (m *userGetMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	id := params.(*userGetMethodParams).ID
+
+	if id <= 0 {
+		return nil, rpcErrors.New(rpcErrors.InvalidParams)
+	}
+
+	return map[string]interface{}{"id": id, "call": atomic.AddInt32(&m.calls, 1)}, nil
+}
+
+type userUpdateMethod struct{}
+
+func (*userUpdateMethod) GetParamsType() interface{} { return new(interface{}) }
+func (*userUpdateMethod) GetName() string            { return "user.update" }
+func (*userUpdateMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, rpcErrors.New(rpcErrors.Internal)
+}
+
+func (*userUpdateMethod) HandleContext(ctx context.Context, params interface{}) (interface{}, jsonrpc.Error) {
+	c, ok := FromContext(ctx)
+	if !ok {
+		return nil, rpcErrors.New(rpcErrors.Internal)
+	}
+
+	if err := c.Invalidate("user.get", params); err != nil {
+		return nil, rpcErrors.New(rpcErrors.Internal)
+	}
+
+	return true, nil
+}
+
+type fakeRecorder struct {
+	hits, misses int
+}
+
+func (r *fakeRecorder) CacheHit(string)  { r.hits++ }
+func (r *fakeRecorder) CacheMiss(string) { r.misses++ }
+
+func newTestRouter(t *testing.T, c *Cache) (*rpcRouter.Router, *userGetMethod) {
+	var (
+		router = rpcRouter.New()
+		method = &userGetMethod{}
+	)
+
+	assert.NoError(t, router.RegisterMethods(method, &userUpdateMethod{}))
+
+	router.Use(c.Middleware)
+
+	return router, method
+}
+
+func TestCache_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c         = New(NewLRUStore(DefaultCapacity))
+		recorder  = &fakeRecorder{}
+		router, _ = newTestRouter(t, c)
+		ctx       = context.Background()
+	)
+
+	c.Recorder = recorder
+
+	call := func(params interface{}) interface{} {
+		result, err := router.InvokeContext(ctx, "user.get", params)
+		assert.Nil(t, err)
+
+		return result
+	}
+
+	assert.Equal(t, map[string]interface{}{"id": 1, "call": int32(1)}, call(map[string]interface{}{"id": 1}))
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "call": float64(1)}, call(map[string]interface{}{"id": 1}))
+
+	// params are canonicalized, and metadata is not a part of the key
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "call": float64(1)}, call(struct {
+		ID   int                    `json:"id"`
+		Meta map[string]interface{} `json:"_meta"`
+	}{ID: 1, Meta: map[string]interface{}{"traceparent": "foo"}}))
+
+	assert.Equal(t, map[string]interface{}{"id": 2, "call": int32(2)}, call(map[string]interface{}{"id": 2}))
+
+	assert.Equal(t, 2, recorder.hits)
+	assert.Equal(t, 2, recorder.misses)
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		_, err := router.InvokeContext(ctx, "user.get", map[string]interface{}{"id": 0})
+		assert.Equal(t, int(rpcErrors.InvalidParams), err.GetCode())
+	}
+
+	assert.Equal(t, 4, recorder.misses)
+
+	// methods without TTL are not cached
+	_, err := router.InvokeContext(ctx, "user.update", map[string]interface{}{"id": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, recorder.misses)
+}
+
+func TestCache_SetTTL(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c              = New(NewLRUStore(DefaultCapacity))
+		router, method = newTestRouter(t, c)
+	)
+
+	c.SetTTL("user.get", 0)
+
+	for i := 0; i < 2; i++ {
+		_, _ = router.InvokeContext(context.Background(), "user.get", map[string]interface{}{"id": 1})
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls))
+
+	c.SetTTL("user.get", time.Hour)
+
+	for i := 0; i < 2; i++ {
+		_, _ = router.InvokeContext(context.Background(), "user.get", map[string]interface{}{"id": 1})
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&method.calls))
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c              = New(NewLRUStore(DefaultCapacity))
+		router, method = newTestRouter(t, c)
+		ctx            = context.Background()
+	)
+
+	for _, id := range []int{1, 2, 1, 2} {
+		_, _ = router.InvokeContext(ctx, "user.get", map[string]interface{}{"id": id})
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls))
+
+	// invalidation by the other handler
+	result, err := router.InvokeContext(ctx, "user.update", map[string]interface{}{"id": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, true, result)
+
+	for _, id := range []int{1, 2} {
+		_, _ = router.InvokeContext(ctx, "user.get", map[string]interface{}{"id": id})
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&method.calls))
+
+	// method invalidation
+	c.InvalidateMethod("user.get")
+
+	for _, id := range []int{1, 2, 1, 2} {
+		_, _ = router.InvokeContext(ctx, "user.get", map[string]interface{}{"id": id})
+	}
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&method.calls))
+}
+
+func TestCache_InvalidateAliasesAndVersions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c              = New(NewLRUStore(DefaultCapacity))
+		router, method = newTestRouter(t, c)
+		v2             = &userGetMethod{}
+		ctx            = context.Background()
+		params         = map[string]interface{}{"id": 1}
+	)
+
+	assert.NoError(t, router.RegisterAlias("profile.get", "user.get"))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", v2))
+
+	for _, name := range []string{"user.get", "profile.get", "user.get@v2", "user.get@v2"} {
+		_, _ = router.InvokeContext(ctx, name, params)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&method.calls)) // alias call result is cached under the method name
+	assert.Equal(t, int32(1), atomic.LoadInt32(&v2.calls))
+
+	// invalidation by the name without version removes the results of the alias and all versions
+	assert.NoError(t, c.Invalidate("user.get", params))
+
+	for _, name := range []string{"profile.get", "user.get@v2"} {
+		_, _ = router.InvokeContext(ctx, name, params)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&v2.calls))
+
+	c.InvalidateMethod("user.get")
+
+	router.DefaultVersion = "v2"
+
+	for _, name := range []string{"profile.get", "user.get"} {
+		_, _ = router.InvokeContext(ctx, name, params)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&v2.calls))
+
+	// TTL, set for the method name, is applied to the alias calls
+	c.SetTTL("user.get", 0)
+
+	for _, name := range []string{"profile.get", "user.get@v2"} {
+		_, _ = router.InvokeContext(ctx, name, params)
+	}
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&v2.calls))
+}
+
+func TestCache_Principals(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c              = New(NewLRUStore(DefaultCapacity))
+		router, method = newTestRouter(t, c)
+		params         = map[string]interface{}{"id": 1}
+		john           = auth.NewContext(context.Background(), &auth.Principal{ID: "john", Authenticator: "jwt"})
+		jane           = auth.NewContext(context.Background(), &auth.Principal{ID: "jane", Authenticator: "jwt"})
+		apiKeyJohn     = auth.NewContext(context.Background(), &auth.Principal{ID: "john", Authenticator: "apikey"})
+	)
+
+	for _, ctx := range []context.Context{john, jane, apiKeyJohn, john, jane, apiKeyJohn} {
+		_, err := router.InvokeContext(ctx, "user.get", params)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&method.calls)) // results are not shared between principals
+
+	// invalidation removes the results of all principals
+	assert.NoError(t, c.Invalidate("user.get", params))
+
+	for _, ctx := range []context.Context{john, jane} {
+		_, _ = router.InvokeContext(ctx, "user.get", params)
+	}
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&method.calls))
+
+	// public results are shared
+	c.SetPublic("user.get", true)
+
+	for _, ctx := range []context.Context{john, jane, apiKeyJohn, context.Background()} {
+		_, _ = router.InvokeContext(ctx, "user.get", params)
+	}
+
+	assert.Equal(t, int32(6), atomic.LoadInt32(&method.calls))
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Store stores the cached results.
+type Store interface {
+	// Get returns the value by key (found is false for unknown or expired values).
+	Get(key string) (value []byte, found bool, err error)
+
+	// Set saves the value for the TTL.
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value.
+	Delete(key string) error
+}
+
+// DefaultCapacity is the default LRUStore capacity.
+const DefaultCapacity = 1024
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUStore is an in-memory store with least recently used values eviction.
+type LRUStore struct {
+	mutex    sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // front - most recently used
+	now      func() time.Time
+}
+
+// NewLRUStore creates new LRU store for capacity values (DefaultCapacity is used for non-positive capacity).
+func NewLRUStore(capacity int) *LRUStore {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &LRUStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get implements Store interface.
+func (s *LRUStore) Get(key string) ([]byte, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(element)
+
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+// Set implements Store interface.
+func (s *LRUStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var expiresAt = s.now().Add(ttl)
+
+	if element, ok := s.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+
+		s.order.MoveToFront(element)
+
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+// Delete implements Store interface.
+func (s *LRUStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// Len returns the stored values count (including expired, but not evicted yet).
+func (s *LRUStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.order.Len()
+}
+
+// remove removes the element. Must be called under lock.
+func (s *LRUStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*lruEntry).key)
+}
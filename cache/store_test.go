@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = NewLRUStore(2)
+		now   = time.Unix(1600000000, 0)
+	)
+
+	store.now = func() time.Time { return now }
+
+	_, found, err := store.Get("foo")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set("foo", []byte(`1`), time.Minute))
+	assert.NoError(t, store.Set("bar", []byte(`2`), time.Minute))
+
+	value, found, err := store.Get("foo") // "foo" becomes most recently used
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte(`1`), value)
+
+	assert.NoError(t, store.Set("baz", []byte(`3`), time.Minute)) // "bar" is evicted
+	assert.Equal(t, 2, store.Len())
+
+	_, found, _ = store.Get("bar")
+	assert.False(t, found)
+
+	// updating
+	assert.NoError(t, store.Set("foo", []byte(`4`), time.Hour))
+	assert.Equal(t, 2, store.Len())
+
+	value, _, _ = store.Get("foo")
+	assert.Equal(t, []byte(`4`), value)
+
+	// expiration
+	now = now.Add(time.Minute)
+
+	_, found, _ = store.Get("baz")
+	assert.False(t, found)
+	assert.Equal(t, 1, store.Len())
+
+	_, found, _ = store.Get("foo")
+	assert.True(t, found)
+
+	// deleting
+	assert.NoError(t, store.Delete("foo"))
+	assert.NoError(t, store.Delete("unknown"))
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestNewLRUStore(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultCapacity, NewLRUStore(0).capacity)
+	assert.Equal(t, 5, NewLRUStore(5).capacity)
+}
@@ -2,3 +2,13 @@ package jsonrpc
 
 // Version is version of current JsonRPC <https://www.jsonrpc.org/specification>
 const Version string = "2.0"
+
+const (
+	// Version10 is legacy JsonRPC version <https://www.jsonrpc.org/specification_v1> (requests of this version have
+	// no version field at all).
+	Version10 string = "1.0"
+
+	// Version11 is legacy JsonRPC version <https://jsonrpc.org/historical/json-rpc-1-1-wd.html> (requests and
+	// responses contain the "version" field).
+	Version11 string = "1.1"
+)
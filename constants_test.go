@@ -8,4 +8,6 @@ import (
 
 func TestConstants(t *testing.T) {
 	assert.Equal(t, "2.0", Version)
+	assert.Equal(t, "1.0", Version10)
+	assert.Equal(t, "1.1", Version11)
 }
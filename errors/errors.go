@@ -14,6 +14,13 @@ const (
 	MethodNotFound Code = -32601
 	InvalidParams  Code = -32602
 	Internal       Code = -32603
+
+	// Extension error codes
+	Timeout          Code = -32001 // server error range (-32000 to -32099)
+	Unauthorized     Code = -32002
+	Forbidden        Code = -32003
+	RateLimited      Code = -32004
+	RequestCancelled Code = -32800 // LSP-compatible
 )
 
 // Error is a wrapper for a JSON interface value. Docs: <https://www.jsonrpc.org/specification#error_object>
@@ -36,6 +43,16 @@ func (code Code) String() string {
 		return "Invalid params"
 	case Internal: // Internal JSON-RPC error
 		return "Internal error"
+	case Timeout: // The method execution timeout exceeded
+		return "Request timeout"
+	case Unauthorized: // The request credentials are missing or invalid
+		return "Unauthorized"
+	case Forbidden: // The client is not allowed to call the method
+		return "Forbidden"
+	case RateLimited: // The client exceeded the rate limit
+		return "Rate limit exceeded"
+	case RequestCancelled: // The request was cancelled by the client
+		return "Request cancelled"
 	}
 
 	return "Unrecognized error code"
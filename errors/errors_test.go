@@ -23,6 +23,11 @@ func TestErrorCodeConstants(t *testing.T) {
 	assert.Equal(t, Code(-32601), MethodNotFound)
 	assert.Equal(t, Code(-32602), InvalidParams)
 	assert.Equal(t, Code(-32603), Internal)
+	assert.Equal(t, Code(-32001), Timeout)
+	assert.Equal(t, Code(-32002), Unauthorized)
+	assert.Equal(t, Code(-32003), Forbidden)
+	assert.Equal(t, Code(-32004), RateLimited)
+	assert.Equal(t, Code(-32800), RequestCancelled)
 }
 
 func TestError_Error(t *testing.T) {
@@ -81,6 +86,11 @@ func TestCode_String(t *testing.T) {
 		{giveCode: MethodNotFound, wantString: "Method not found"},
 		{giveCode: InvalidParams, wantString: "Invalid params"},
 		{giveCode: Internal, wantString: "Internal error"},
+		{giveCode: Timeout, wantString: "Request timeout"},
+		{giveCode: Unauthorized, wantString: "Unauthorized"},
+		{giveCode: Forbidden, wantString: "Forbidden"},
+		{giveCode: RateLimited, wantString: "Rate limit exceeded"},
+		{giveCode: RequestCancelled, wantString: "Request cancelled"},
 		{giveCode: Code(0), wantString: "Unrecognized error code"},
 		{giveCode: Code(666), wantString: "Unrecognized error code"},
 	}
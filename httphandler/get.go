@@ -0,0 +1,226 @@
+package httphandler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+type (
+	// SafeMethod is an optional jsonrpc.Method interface. Only methods, marked as safe (read-only and idempotent), can
+	// be invoked using GET requests.
+	SafeMethod interface {
+		IsSafe() bool
+	}
+
+	// CacheableMethod is an optional jsonrpc.Method interface, that allows to override the handler CacheMaxAge.
+	CacheableMethod interface {
+		CacheMaxAge() time.Duration
+	}
+)
+
+// serveGet invokes safe methods using query string. Two forms are supported:
+//
+//	GET /rpc?method=user.get&params=<urlencoded json>&id=1
+//	GET /rpc/user.get?id=5 (path after the PathPrefix is a method name, and query values are the named params)
+//
+// Responses contain "ETag" and "Cache-Control" headers.
+func (handler *Handler) serveGet(w http.ResponseWriter, r *http.Request) {
+	request, method, err := handler.requestFromQuery(r)
+	if err != nil {
+		handler.writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if !handler.isSafe(method) {
+		err := rpcErrors.New(rpcErrors.InvalidRequest)
+		err.Data = "method is not allowed for GET requests"
+
+		w.Header().Set("Allow", http.MethodPost)
+		handler.writeError(w, http.StatusMethodNotAllowed, err)
+
+		return
+	}
+
+	var (
+		result = handler.kernel.HandleJSONRequestContext(r.Context(), request)
+		etag   = handler.etag(result)
+	)
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", handler.cacheControl(r, method, result))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(result)
+	}
+}
+
+// requestFromQuery builds JSON request using query string (and path).
+func (handler *Handler) requestFromQuery(r *http.Request) ([]byte, string, *rpcErrors.Error) {
+	var (
+		query   = r.URL.Query()
+		request = map[string]interface{}{"jsonrpc": jsonrpc.Version, "id": 0}
+		method  = query.Get("method")
+	)
+
+	if method != "" { // query form
+		if raw := query.Get("params"); raw != "" {
+			var params interface{}
+
+			if err := handler.json.UnmarshalFromString(raw, &params); err != nil {
+				rpcErr := rpcErrors.New(rpcErrors.Parse)
+				rpcErr.Data = err.Error()
+
+				return nil, "", rpcErr
+			}
+
+			request["params"] = params
+		}
+
+		if id := query.Get("id"); id != "" {
+			if i, err := strconv.Atoi(id); err == nil {
+				request["id"] = i
+			} else {
+				request["id"] = id
+			}
+		}
+	} else if handler.PathPrefix != "" && strings.HasPrefix(r.URL.Path, handler.PathPrefix) { // path form
+		method = strings.TrimPrefix(r.URL.Path, handler.PathPrefix)
+
+		if len(query) > 0 {
+			params := make(map[string]interface{}, len(query))
+
+			for key := range query {
+				var value interface{}
+
+				// values, that are not valid JSON (e.g. `foo`), are used as strings
+				if err := handler.json.UnmarshalFromString(query.Get(key), &value); err != nil {
+					value = query.Get(key)
+				}
+
+				params[key] = value
+			}
+
+			request["params"] = params
+		}
+	}
+
+	if method == "" || strings.Contains(method, "/") {
+		rpcErr := rpcErrors.New(rpcErrors.InvalidRequest)
+		rpcErr.Data = "method is not specified"
+
+		return nil, "", rpcErr
+	}
+
+	request["method"] = method
+
+	data, _ := handler.json.Marshal(request)
+
+	return data, method, nil
+}
+
+func (handler *Handler) isSafe(methodName string) bool {
+	lookup, ok := handler.kernel.Router().(methodLookup)
+	if !ok {
+		return false
+	}
+
+	method, found := lookup.LookupMethod(methodName)
+	if !found {
+		return false
+	}
+
+	safe, ok := method.(SafeMethod)
+
+	return ok && safe.IsSafe()
+}
+
+// cacheControl returns "Cache-Control" header value for the response.
+func (handler *Handler) cacheControl(r *http.Request, methodName string, result []byte) string {
+	var response struct {
+		Error interface{} `json:"error"`
+	}
+
+	if err := handler.json.Unmarshal(result, &response); err != nil || response.Error != nil {
+		return "no-store"
+	}
+
+	var maxAge = handler.CacheMaxAge
+
+	if lookup, ok := handler.kernel.Router().(methodLookup); ok {
+		if method, found := lookup.LookupMethod(methodName); found {
+			if cacheable, ok := method.(CacheableMethod); ok {
+				maxAge = cacheable.CacheMaxAge()
+			}
+		}
+	}
+
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+
+	var visibility = "private"
+
+	if handler.PublicCache && !handler.hasCredentials(r) {
+		visibility = "public"
+	}
+
+	return visibility + ", max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
+
+// hasCredentials checks if the request has credentials: any of the CredentialHeaders or TLS client certificate.
+func (handler *Handler) hasCredentials(r *http.Request) bool {
+	for _, name := range handler.CredentialHeaders {
+		if r.Header.Get(name) != "" {
+			return true
+		}
+	}
+
+	return r.TLS != nil && len(r.TLS.PeerCertificates) > 0
+}
+
+// etag calculates the response ETag. Response is re-encoded with sorted keys before hashing, because the maps
+// encoding order is not stable.
+func (handler *Handler) etag(result []byte) string {
+	var value interface{}
+
+	if err := handler.json.Unmarshal(result, &value); err == nil {
+		if canonical, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(value); err == nil {
+			result = canonical
+		}
+	}
+
+	sum := sha256.Sum256(result)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"` //nolint:gomnd
+}
+
+// etagMatches checks "If-None-Match" header value against the ETag.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,244 @@
+package httphandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+)
+
+func newGetTestHandler(t *testing.T) *Handler {
+	handler := newTestHandler(t)
+	handler.PathPrefix = "/rpc/"
+
+	return handler
+}
+
+func TestHandler_ServeGet(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name             string
+		giveURL          string
+		wantCode         int
+		wantJSON         string
+		wantCacheControl string
+		wantAllow        string
+	}{
+		{
+			name:             "query form",
+			giveURL:          `/rpc?method=user.get&params=%7B%22id%22%3A5%7D&id=1`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "result": {"id": 5, "name": "John"}, "id": 1}`,
+			wantCacheControl: "private, max-age=60",
+		},
+		{
+			name:             "query form with string id",
+			giveURL:          `/rpc?method=echo&params=[1,"a"]&id=foo`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "result": [1, "a"], "id": "foo"}`,
+			wantCacheControl: "no-cache",
+		},
+		{
+			name:             "query form without params and id",
+			giveURL:          `/rpc?method=echo`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "result": null, "id": 0}`,
+			wantCacheControl: "no-cache",
+		},
+		{
+			name:     "query form with wrong params",
+			giveURL:  `/rpc?method=echo&params=[1`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:             "path form",
+			giveURL:          `/rpc/user.get?id=5`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "result": {"id": 5, "name": "John"}, "id": 0}`,
+			wantCacheControl: "private, max-age=60",
+		},
+		{
+			name:             "path form with string values",
+			giveURL:          `/rpc/echo?a=foo&b=007&c="7"&d=true`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "result": {"a": "foo", "b": "007", "c": "7", "d": true}, "id": 0}`,
+			wantCacheControl: "no-cache",
+		},
+		{
+			name:             "error response is not cached",
+			giveURL:          `/rpc/user.get?id=0`,
+			wantCode:         http.StatusOK,
+			wantJSON:         `{"jsonrpc": "2.0", "error": {"code": -32602, "message": "Invalid params"}, "id": 0}`,
+			wantCacheControl: "no-store",
+		},
+		{
+			name:      "unsafe method",
+			giveURL:   `/rpc/user.delete`,
+			wantCode:  http.StatusMethodNotAllowed,
+			wantAllow: http.MethodPost,
+		},
+		{
+			name:      "unknown method",
+			giveURL:   `/rpc?method=foo`,
+			wantCode:  http.StatusMethodNotAllowed,
+			wantAllow: http.MethodPost,
+		},
+		{
+			name:     "without method",
+			giveURL:  `/`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "mount path without method",
+			giveURL:  `/rpc`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "path without prefix",
+			giveURL:  `/foo/user.get?id=5`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "nested path",
+			giveURL:  `/rpc/foo/user.get?id=5`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				handler = newGetTestHandler(t)
+				req, _  = http.NewRequest(http.MethodGet, "http://rpc"+tt.giveURL, nil)
+				rr      = httptest.NewRecorder()
+			)
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantAllow, rr.Header().Get("Allow"))
+
+			if tt.wantJSON != "" {
+				assert.JSONEq(t, tt.wantJSON, rr.Body.String())
+				assert.Equal(t, tt.wantCacheControl, rr.Header().Get("Cache-Control"))
+				assert.NotEmpty(t, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_ServeGetETag(t *testing.T) {
+	t.Parallel()
+
+	handler := newGetTestHandler(t)
+	handler.CacheMaxAge = 0
+
+	req, _ := http.NewRequest(http.MethodGet, "http://rpc/rpc/user.get?id=1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	for _, ifNoneMatch := range []string{etag, `"foo", W/` + etag, "*"} {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rr = httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+	}
+
+	req.Header.Set("If-None-Match", `"foo"`)
+	rr = httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// other result - other etag
+	req, _ = http.NewRequest(http.MethodGet, "http://rpc/rpc/user.get?id=2", nil)
+	rr = httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+}
+
+func TestHandler_ServeHead(t *testing.T) {
+	t.Parallel()
+
+	req, _ := http.NewRequest(http.MethodHead, "http://rpc/rpc/user.get?id=1", nil)
+	rr := httptest.NewRecorder()
+
+	newGetTestHandler(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	assert.Empty(t, rr.Body.String())
+}
+
+type plainRouter struct {
+	jsonrpc.Router
+}
+
+func TestHandler_ServeGetWithoutMethodsLookup(t *testing.T) {
+	t.Parallel()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://rpc/rpc/user.get?id=1", nil)
+	rr := httptest.NewRecorder()
+
+	handler := New(rpcKernel.New(plainRouter{}))
+	handler.PathPrefix = "/rpc/"
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestHandler_ServeGetPublicCache(t *testing.T) {
+	t.Parallel()
+
+	handler := newGetTestHandler(t)
+	handler.PublicCache = true
+
+	for header, want := range map[string]string{
+		"":              "public, max-age=60",
+		"Authorization": "private, max-age=60",
+		"Cookie":        "private, max-age=60",
+		"X-Api-Key":     "private, max-age=60",
+		"X-Signature":   "private, max-age=60",
+		"X-Token":       "public, max-age=60",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "http://rpc/rpc/user.get?id=1", nil)
+		rr := httptest.NewRecorder()
+
+		if header != "" {
+			req.Header.Set(header, "secret")
+		}
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, want, rr.Header().Get("Cache-Control"), header)
+	}
+
+	// custom credential headers
+	handler.CredentialHeaders = append(handler.CredentialHeaders, "X-Token")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://rpc/rpc/user.get?id=1", nil)
+	req.Header.Set("X-Token", "secret")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "private, max-age=60", rr.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,157 @@
+// Package httphandler provides HTTP transport for the kernel.
+package httphandler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+)
+
+const contentTypeJSON = "application/json; charset=utf-8"
+
+// DefaultCredentialHeaders are the request headers with credentials: "Authorization", "Cookie", API key and HMAC
+// signature headers (default headers of the auth package authenticators).
+var DefaultCredentialHeaders = []string{"Authorization", "Cookie", "X-Api-Key", "X-Signature"} //nolint:gochecknoglobals
+
+// methodLookup is implemented by the default router.
+type methodLookup interface {
+	LookupMethod(methodName string) (jsonrpc.Method, bool)
+}
+
+// Handler is an HTTP handler for the RPC requests. POST requests are processed as usual JsonRPC requests, and GET
+// requests are allowed for the safe methods only (see SafeMethod interface).
+//
+// Server-Sent Events are used when the client accepts "text/event-stream": POST request streams notifications, sent
+// by the invoked methods (e.g. progress), and the final response; GET request opens a long-lived channel for the
+// notifications (e.g. subscriptions) - POST requests with the channel session ID in the SessionHeader are invoked
+// with the channel session.
+type Handler struct {
+	kernel *rpcKernel.Kernel
+	json   jsoniter.API
+
+	mutex    sync.RWMutex
+	sessions map[string]*sseSession // long-lived SSE channels
+
+	// MaxBodyBytes limits request body size (zero means "without limit").
+	MaxBodyBytes int64
+
+	// CacheMaxAge is used for the "Cache-Control" header of successful GET responses (methods can override it
+	// using CacheableMethod interface). Zero value means "revalidate on each request" (using ETag).
+	CacheMaxAge time.Duration
+
+	// PublicCache allows shared caches (proxies, CDNs) to store successful GET responses (by default they are
+	// private). Responses of the requests with credentials (any of CredentialHeaders or TLS client certificate) are
+	// private anyway.
+	PublicCache bool
+
+	// CredentialHeaders are the request headers with credentials (DefaultCredentialHeaders by default). Custom
+	// authenticators headers should be added here, when PublicCache is used.
+	CredentialHeaders []string
+
+	// PathPrefix enables the path form of GET requests: the path after the prefix is a method name (e.g. `/rpc/`
+	// for `GET /rpc/user.get?id=5`). Path form is disabled by default.
+	PathPrefix string
+
+	// SSEKeepAlive is an interval of the keep-alive comments sending into the event streams (zero means "disabled").
+	SSEKeepAlive time.Duration
+}
+
+// New creates new HTTP handler for the kernel.
+func New(kernel *rpcKernel.Kernel) *Handler {
+	return &Handler{
+		kernel:   kernel,
+		json:     jsoniter.ConfigFastest,
+		sessions: make(map[string]*sseSession),
+
+		CredentialHeaders: append([]string(nil), DefaultCredentialHeaders...),
+	}
+}
+
+// ServeHTTP implements http.Handler interface.
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w, r = metadata.WrapHTTP(w, r)
+
+	switch r.Method {
+	case http.MethodPost:
+		handler.servePost(w, r)
+
+	case http.MethodGet, http.MethodHead:
+		if r.Method == http.MethodGet && acceptsEventStream(r) {
+			handler.serveChannel(w, r)
+		} else {
+			handler.serveGet(w, r)
+		}
+
+	default:
+		w.Header().Set("Allow", http.MethodPost+", "+http.MethodGet)
+		handler.writeError(w, http.StatusMethodNotAllowed, rpcErrors.New(rpcErrors.InvalidRequest))
+	}
+}
+
+func (handler *Handler) servePost(w http.ResponseWriter, r *http.Request) {
+	if handler.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, handler.MaxBodyBytes)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		rpcErr := rpcErrors.New(rpcErrors.InvalidRequest)
+		rpcErr.Data = err.Error()
+
+		handler.writeError(w, http.StatusRequestEntityTooLarge, rpcErr)
+
+		return
+	}
+
+	if acceptsEventStream(r) {
+		handler.serveEventStream(w, r, body)
+
+		return
+	}
+
+	var ctx = r.Context()
+
+	if r.Header.Get(SessionHeader) != "" {
+		s, ok := handler.channelSession(r)
+		if !ok {
+			rpcErr := rpcErrors.New(rpcErrors.InvalidRequest)
+			rpcErr.Data = "unknown session"
+
+			handler.writeError(w, http.StatusBadRequest, rpcErr)
+
+			return
+		}
+
+		ctx = withSession(ctx, s)
+	}
+
+	result := handler.kernel.HandleJSONRequestContext(ctx, body)
+
+	if len(result) == 0 { // notifications only
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(result)
+}
+
+func (handler *Handler) writeError(w http.ResponseWriter, status int, err *rpcErrors.Error) {
+	data, _ := handler.json.Marshal(rpcResponse.Response{Version: jsonrpc.Version, Error: err})
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+
+	_, _ = w.Write(data)
+}
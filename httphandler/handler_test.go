@@ -0,0 +1,117 @@
+package httphandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethods(
+		&userGetMethod{}, &echoMethod{}, &userDeleteMethod{}, &progressMethod{}, &whoamiMethod{},
+	))
+
+	return New(rpcKernel.New(router))
+}
+
+func TestHandler_ServePost(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		giveBody     string
+		giveMaxBytes int64
+		wantCode     int
+		wantJSON     string
+	}{
+		{
+			name:     "regular request",
+			giveBody: `{"jsonrpc": "2.0", "method": "user.get", "params": {"id": 1}, "id": 1}`,
+			wantCode: http.StatusOK,
+			wantJSON: `{"jsonrpc": "2.0", "result": {"id": 1, "name": "John"}, "id": 1}`,
+		},
+		{
+			name:     "notification",
+			giveBody: `{"jsonrpc": "2.0", "method": "user.get", "params": {"id": 1}}`,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "parse error",
+			giveBody: `{`,
+			wantCode: http.StatusOK,
+			wantJSON: `{"jsonrpc": "2.0", "error": {"code": -32700, "message": "Parse error"}}`,
+		},
+		{
+			name:         "too large body",
+			giveBody:     `{"jsonrpc": "2.0", "method": "user.get", "params": {"id": 1}, "id": 1}`,
+			giveMaxBytes: 10,
+			wantCode:     http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				handler = newTestHandler(t)
+				req, _  = http.NewRequest(http.MethodPost, "http://rpc/rpc", strings.NewReader(tt.giveBody))
+				rr      = httptest.NewRecorder()
+			)
+
+			handler.MaxBodyBytes = tt.giveMaxBytes
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+
+			if tt.wantJSON != "" {
+				assert.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.wantJSON, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ServeMetadata(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body   = `{"jsonrpc": "2.0", "method": "whoami", "id": 1}`
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/rpc", strings.NewReader(body))
+		rr     = httptest.NewRecorder()
+	)
+
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-User", "john")
+
+	newTestHandler(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "john", rr.Header().Get("X-User"))
+	assert.Equal(t, "seen=1", rr.Header().Get("Set-Cookie"))
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "result": {"transport": "http", "addr": "10.0.0.1:1234", "session": ""}, "id": 1}`,
+		rr.Body.String(),
+	)
+}
+
+func TestHandler_ServeWrongMethod(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPut, "http://rpc/rpc", nil)
+		rr     = httptest.NewRecorder()
+	)
+
+	newTestHandler(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Equal(t, "POST, GET", rr.Header().Get("Allow"))
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}}`, rr.Body.String())
+}
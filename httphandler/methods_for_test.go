@@ -0,0 +1,96 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+type (
+	userGetMethod       struct{}
+	userGetMethodParams struct {
+		ID int `json:"id"`
+	}
+)
+
+func (*userGetMethod) GetParamsType() interface{} { return &userGetMethodParams{} }
+func (*userGetMethod) GetName() string            { return "user.get" }
+func (*userGetMethod) IsSafe() bool               { return true }
+func (*userGetMethod) CacheMaxAge() time.Duration { return time.Minute }
+func (*userGetMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	p := params.(*userGetMethodParams)
+
+	if p.ID <= 0 {
+		return nil, rpcErrors.New(rpcErrors.InvalidParams)
+	}
+
+	return map[string]interface{}{"id": p.ID, "name": "John"}, nil
+}
+
+type (
+	echoMethod struct{}
+)
+
+func (*echoMethod) GetParamsType() interface{} { return new(interface{}) }
+func (*echoMethod) GetName() string            { return "echo" }
+func (*echoMethod) IsSafe() bool               { return true }
+func (*echoMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	return params, nil
+}
+
+type (
+	userDeleteMethod struct{}
+)
+
+func (*userDeleteMethod) GetParamsType() interface{} { return nil }
+func (*userDeleteMethod) GetName() string            { return "user.delete" }
+func (*userDeleteMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return true, nil
+}
+
+type (
+	progressMethod struct{}
+)
+
+func (*progressMethod) GetParamsType() interface{} { return nil }
+func (*progressMethod) GetName() string            { return "progress" }
+func (*progressMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return "done", nil
+}
+
+func (*progressMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	if s, ok := session.FromContext(ctx); ok {
+		for i := 1; i <= 2; i++ {
+			_ = s.Notify("progress", map[string]int{"step": i})
+		}
+	}
+
+	return "done", nil
+}
+
+type (
+	whoamiMethod struct{}
+)
+
+func (*whoamiMethod) GetParamsType() interface{} { return nil }
+func (*whoamiMethod) GetName() string            { return "whoami" }
+func (*whoamiMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, rpcErrors.New(rpcErrors.Internal)
+}
+
+func (*whoamiMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil, rpcErrors.New(rpcErrors.Internal)
+	}
+
+	md.SetHeader("X-User", md.Get("X-User"))
+	md.SetCookie(&http.Cookie{Name: "seen", Value: "1"})
+
+	return map[string]string{"transport": md.Transport, "addr": md.RemoteAddr, "session": md.SessionID}, nil
+}
@@ -0,0 +1,235 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+const (
+	contentTypeEventStream = "text/event-stream"
+
+	// SessionHeader is used for binding POST requests to the long-lived SSE channel session (subscriptions, created
+	// by such requests, send notifications into the channel).
+	SessionHeader = "X-Jsonrpc-Session"
+
+	// SSE event names.
+	EventSession      = "session"      // first event of the long-lived channel, data is `{"session": "<ID>"}`
+	EventNotification = "notification" // server-to-client notification (JsonRPC request without ID)
+	EventResponse     = "response"     // JsonRPC response (the last event of the POST request stream)
+)
+
+var errSessionClosed = errors.New("jsonrpc: session is closed")
+
+// sseSession is a session, that sends notifications as SSE events.
+type sseSession struct {
+	id      string
+	w       http.ResponseWriter
+	flusher http.Flusher
+	json    jsoniter.API
+
+	mutex     sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newSSESession(w http.ResponseWriter, json jsoniter.API) (*sseSession, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", contentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no") // disables nginx buffering
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &sseSession{
+		id:      session.NewID(),
+		w:       w,
+		flusher: flusher,
+		json:    json,
+		done:    make(chan struct{}),
+	}, true
+}
+
+// ID implements session.Session interface.
+func (s *sseSession) ID() string { return s.id }
+
+// Done implements session.Session interface.
+func (s *sseSession) Done() <-chan struct{} { return s.done }
+
+// Notify implements session.Session interface.
+func (s *sseSession) Notify(method string, params interface{}) error {
+	data, err := s.json.Marshal(rpcRequest.Notification{Version: jsonrpc.Version, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	return s.send(EventNotification, data)
+}
+
+// send writes an event into the stream. Data should not contain new lines (compact JSON).
+func (s *sseSession) send(event string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	select {
+	case <-s.done:
+		return errSessionClosed
+	default:
+	}
+
+	if _, err := s.w.Write([]byte("event: " + event + "\ndata: " + string(data) + "\n\n")); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+
+	return nil
+}
+
+// ping writes a comment into the stream (keeps connection alive through the proxies).
+func (s *sseSession) ping() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	select {
+	case <-s.done:
+	default:
+		_, _ = s.w.Write([]byte(": ping\n\n"))
+		s.flusher.Flush()
+	}
+}
+
+func (s *sseSession) close() {
+	s.closeOnce.Do(func() {
+		s.mutex.Lock()
+		close(s.done)
+		s.mutex.Unlock()
+	})
+}
+
+// acceptsEventStream checks the request "Accept" header.
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), contentTypeEventStream)
+}
+
+// serveEventStream processes POST request body and streams notifications (e.g. progress), sent by the invoked
+// methods, and the final response.
+func (handler *Handler) serveEventStream(w http.ResponseWriter, r *http.Request, body []byte) {
+	s, ok := newSSESession(w, handler.json)
+	if !ok {
+		handler.writeError(w, http.StatusNotImplemented, errStreamingUnsupported())
+
+		return
+	}
+
+	defer s.close()
+
+	stop := handler.keepAlive(r.Context(), s)
+	defer stop()
+
+	if result := handler.kernel.HandleJSONRequestContext(withSession(r.Context(), s), body); len(result) > 0 {
+		_ = s.send(EventResponse, result)
+	}
+}
+
+// serveChannel opens long-lived SSE channel for the notifications. POST requests with SessionHeader are invoked
+// with the channel session.
+func (handler *Handler) serveChannel(w http.ResponseWriter, r *http.Request) {
+	s, ok := newSSESession(w, handler.json)
+	if !ok {
+		handler.writeError(w, http.StatusNotImplemented, errStreamingUnsupported())
+
+		return
+	}
+
+	handler.mutex.Lock()
+	handler.sessions[s.id] = s
+	handler.mutex.Unlock()
+
+	defer func() {
+		handler.mutex.Lock()
+		delete(handler.sessions, s.id)
+		handler.mutex.Unlock()
+
+		s.close()
+	}()
+
+	data, _ := handler.json.Marshal(map[string]string{"session": s.id})
+
+	if err := s.send(EventSession, data); err != nil {
+		return
+	}
+
+	stop := handler.keepAlive(r.Context(), s)
+	defer stop()
+
+	<-r.Context().Done()
+}
+
+// keepAlive starts pinging the session (when SSEKeepAlive is set) until the context is done or stop is called.
+func (handler *Handler) keepAlive(ctx context.Context, s *sseSession) (stop func()) {
+	if handler.SSEKeepAlive <= 0 {
+		return func() {}
+	}
+
+	var (
+		ticker = time.NewTicker(handler.SSEKeepAlive)
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.ping()
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// channelSession returns the long-lived channel session, requested using SessionHeader.
+func (handler *Handler) channelSession(r *http.Request) (*sseSession, bool) {
+	handler.mutex.RLock()
+	s, ok := handler.sessions[r.Header.Get(SessionHeader)]
+	handler.mutex.RUnlock()
+
+	return s, ok
+}
+
+// withSession attaches the session to the context (and its ID to the call metadata).
+func withSession(ctx context.Context, s *sseSession) context.Context {
+	if md, ok := metadata.FromContext(ctx); ok {
+		md.SessionID = s.id
+	}
+
+	return session.WithSession(ctx, s)
+}
+
+func errStreamingUnsupported() *rpcErrors.Error {
+	err := rpcErrors.New(rpcErrors.Internal)
+	err.Data = "streaming is not supported"
+
+	return err
+}
@@ -0,0 +1,163 @@
+package httphandler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ServeEventStream(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/rpc", strings.NewReader(
+			`{"jsonrpc": "2.0", "method": "progress", "id": 1}`,
+		))
+		rr = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	newTestHandler(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "event: notification\n"+
+		`data: {"jsonrpc":"2.0","method":"progress","params":{"step":1}}`+"\n\n"+
+		"event: notification\n"+
+		`data: {"jsonrpc":"2.0","method":"progress","params":{"step":2}}`+"\n\n"+
+		"event: response\n"+
+		`data: {"jsonrpc":"2.0","result":"done","id":1}`+"\n\n", rr.Body.String())
+}
+
+func TestHandler_ServeEventStreamNotification(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/rpc", strings.NewReader(
+			`{"jsonrpc": "2.0", "method": "user.get", "params": {"id": 1}}`,
+		))
+		rr = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	newTestHandler(t).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+type notFlushableWriter struct {
+	http.ResponseWriter
+}
+
+func TestHandler_ServeEventStreamWithoutFlusher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/rpc", strings.NewReader(`{}`))
+		rr     = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	newTestHandler(t).ServeHTTP(notFlushableWriter{rr}, req)
+
+	assert.Equal(t, http.StatusNotImplemented, rr.Code)
+}
+
+func TestHandler_ServeChannel(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler(t)
+	handler.SSEKeepAlive = time.Millisecond * 10
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var (
+		reader = bufio.NewReader(resp.Body)
+		next   = func() (event, data string) { // reads the next event (skipping comments)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return "", ""
+				}
+
+				switch {
+				case strings.HasPrefix(line, "event: "):
+					event = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+				case strings.HasPrefix(line, "data: "):
+					data = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+				case line == "\n" && event != "":
+					return event, data
+				}
+			}
+		}
+	)
+
+	event, data := next()
+	assert.Equal(t, EventSession, event)
+	assert.Regexp(t, `^{"session":"[0-9a-f]{32}"}$`, data)
+
+	sessionID := data[len(`{"session":"`) : len(data)-2]
+
+	// request, bound to the channel
+	post, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(
+		`{"jsonrpc": "2.0", "method": "progress", "id": 1}`,
+	))
+	post.Header.Set(SessionHeader, sessionID)
+
+	postResp, err := http.DefaultClient.Do(post)
+	assert.NoError(t, err)
+
+	_ = postResp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, postResp.StatusCode)
+
+	for i := 1; i <= 2; i++ {
+		event, data = next()
+		assert.Equal(t, EventNotification, event)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","method":"progress","params":{"step":`+string(rune('0'+i))+`}}`, data)
+	}
+
+	// unknown session
+	post, _ = http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+	post.Header.Set(SessionHeader, "foo")
+
+	postResp, err = http.DefaultClient.Do(post)
+	assert.NoError(t, err)
+
+	_ = postResp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, postResp.StatusCode)
+
+	// channel closing
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		_, ok := handler.channelSession(&http.Request{Header: http.Header{SessionHeader: {sessionID}}})
+
+		return !ok
+	}, time.Second, time.Millisecond*5)
+}
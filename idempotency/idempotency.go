@@ -0,0 +1,291 @@
+// Package idempotency provides duplicate calls suppression using client-supplied idempotency keys. The first
+// successful result is stored for the TTL and replayed for the calls with the same key:
+//
+//	router.Use(authentication.Middleware, idempotency.New(idempotency.NewMemoryStore()).Middleware)
+//
+// Key is passed in the KeyHeader (for the transports with headers) or in the request params MetaField, e.g.:
+// `"params": {"_meta": {"idempotency_key": "..."}, ...}`. Keys are scoped by the authenticated principal (see
+// package auth) and the canonical method name, so the same key can be used for the different methods of a batch.
+// Calls with keys must be authenticated, unless AllowAnonymous is set.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// Idempotency key sources and the replay marker.
+const (
+	KeyHeader      = "Idempotency-Key"
+	MetaField      = "_meta"
+	MetaKeyField   = "idempotency_key"
+	ReplayedHeader = "Idempotent-Replayed" // response header, that is set for the replayed results
+)
+
+// DefaultTTL is the default stored results TTL.
+const DefaultTTL = 24 * time.Hour
+
+// CoalescedMethod is an optional jsonrpc.Method interface, that allows method to opt into the coalescing of the
+// concurrent identical calls without keys (see Idempotency.Coalesce).
+type CoalescedMethod interface {
+	CoalesceCalls() bool
+}
+
+// call is an in-flight call.
+type call struct {
+	done        chan struct{}
+	fingerprint string
+	result      interface{}
+	err         jsonrpc.Error
+}
+
+// Idempotency suppresses duplicate calls. Only successful results are stored (failed calls can be retried), and
+// replayed results are decoded from JSON (so structures are replayed as maps). Call with the key of an in-flight call
+// is rejected with the errors.InvalidRequest error (or waits for its result in the coalescing mode), as well as a call
+// with the key, that was used with the other params.
+type Idempotency struct {
+	store Store
+	json  jsoniter.API
+
+	mutex    sync.Mutex
+	inFlight map[string]*call
+
+	// TTL is the stored results TTL (DefaultTTL by default).
+	TTL time.Duration
+
+	// Coalesce enables in-flight coalescing (singleflight): concurrent calls with the same key wait for the first
+	// call result. Calls without keys of the methods, that opt into it (see CoalescedMethod), are coalesced too, when
+	// they have the same principal, method and params.
+	Coalesce bool
+
+	// AllowAnonymous allows the calls with keys without authenticated principal. Keys of all anonymous clients share
+	// the same namespace, so one client can replay the result of another using its key - keys must be unguessable
+	// (e.g. random UUIDs) and must not be disclosed.
+	AllowAnonymous bool
+}
+
+// New creates new Idempotency.
+func New(store Store) *Idempotency {
+	return &Idempotency{
+		store:    store,
+		json:     jsoniter.ConfigCompatibleWithStandardLibrary, // sorted map keys are required for fingerprints
+		inFlight: make(map[string]*call),
+		TTL:      DefaultTTL,
+	}
+}
+
+// Middleware is a router middleware, that suppresses duplicate calls.
+func (i *Idempotency) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		key := Key(ctx, params)
+
+		if key == "" && !(i.Coalesce && coalesced(ctx)) {
+			return next(ctx, methodName, params)
+		}
+
+		principal, authenticated := scope(ctx)
+
+		if key != "" && !authenticated && !i.AllowAnonymous {
+			err := rpcErrors.New(rpcErrors.Unauthorized)
+			err.Data = "idempotency key requires authentication"
+
+			return nil, err
+		}
+
+		fingerprint, err := i.fingerprint(params)
+		if err != nil {
+			return next(ctx, methodName, params)
+		}
+
+		var name = methodName
+
+		if canonical, resolved := rpcRouter.MethodNameFromContext(ctx); resolved {
+			name = canonical
+		}
+
+		var storeKey = principal + "\x00" + name + "\x00"
+
+		if key != "" {
+			storeKey += "key\x00" + key
+		} else {
+			storeKey += "params\x00" + fingerprint // identical calls coalescing only
+		}
+
+		c, leader := i.acquire(storeKey, fingerprint)
+
+		if !leader {
+			if !i.Coalesce {
+				return nil, invalidRequest("request with the same idempotency key is in progress")
+			}
+
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, contextError(ctx)
+			}
+
+			if c.fingerprint != fingerprint {
+				return nil, invalidRequest("idempotency key was used with other params")
+			}
+
+			return c.result, c.err
+		}
+
+		defer i.release(storeKey, c)
+
+		if key != "" {
+			entry, found, storeErr := i.store.Get(storeKey)
+			if storeErr != nil {
+				c.err = internal(storeErr)
+
+				return nil, c.err
+			}
+
+			if found {
+				if entry.Fingerprint != fingerprint {
+					c.err = invalidRequest("idempotency key was used with other params")
+				} else if decodeErr := i.json.Unmarshal(entry.Result, &c.result); decodeErr != nil {
+					c.err = internal(decodeErr)
+				} else if md, ok := metadata.FromContext(ctx); ok {
+					md.SetHeader(ReplayedHeader, "true")
+				}
+
+				return c.result, c.err
+			}
+		}
+
+		c.result, c.err = next(ctx, methodName, params)
+
+		if key != "" && c.err == nil {
+			if data, marshalErr := i.json.Marshal(c.result); marshalErr == nil {
+				_ = i.store.Set(storeKey, Entry{Fingerprint: fingerprint, Result: data, ExpiresAt: time.Now().Add(i.TTL)})
+			}
+		}
+
+		return c.result, c.err
+	}
+}
+
+// acquire registers the in-flight call (leader is true), or returns the registered one.
+func (i *Idempotency) acquire(key, fingerprint string) (c *call, leader bool) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if c, exists := i.inFlight[key]; exists {
+		return c, false
+	}
+
+	c = &call{done: make(chan struct{}), fingerprint: fingerprint}
+	i.inFlight[key] = c
+
+	return c, true
+}
+
+// release completes the in-flight call.
+func (i *Idempotency) release(key string, c *call) {
+	i.mutex.Lock()
+	delete(i.inFlight, key)
+	i.mutex.Unlock()
+
+	close(c.done)
+}
+
+// fingerprint returns the hash of canonicalized params (object keys are sorted).
+func (i *Idempotency) fingerprint(params interface{}) (string, error) {
+	data, err := i.json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	var decoded interface{}
+
+	if err = i.json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+
+	if p, ok := decoded.(map[string]interface{}); ok {
+		delete(p, MetaField) // metadata (e.g. the key itself or trace context) is not a part of the params
+	}
+
+	if data, err = i.json.Marshal(decoded); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Key returns the call idempotency key from the metadata or params (empty string is returned, when it is missing).
+func Key(ctx context.Context, params interface{}) string {
+	if md, ok := metadata.FromContext(ctx); ok {
+		if key := md.Get(KeyHeader); key != "" {
+			return key
+		}
+	}
+
+	if p, ok := params.(map[string]interface{}); ok {
+		if meta, isMap := p[MetaField].(map[string]interface{}); isMap {
+			if key, isString := meta[MetaKeyField].(string); isString {
+				return key
+			}
+		}
+	}
+
+	return ""
+}
+
+// scope returns the keys scope (authenticated principal).
+func scope(ctx context.Context) (string, bool) {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.Authenticator + ":" + principal.ID, true
+	}
+
+	return "", false
+}
+
+// coalesced checks if the method, that is going to be invoked, opts into the calls without keys coalescing.
+func coalesced(ctx context.Context) bool {
+	method, found := rpcRouter.MethodFromContext(ctx)
+	if !found {
+		return false
+	}
+
+	m, ok := method.(CoalescedMethod)
+
+	return ok && m.CoalesceCalls()
+}
+
+// contextError converts the done context error into RPC error.
+func contextError(ctx context.Context) *rpcErrors.Error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return rpcErrors.New(rpcErrors.Timeout)
+	}
+
+	return rpcErrors.New(rpcErrors.RequestCancelled)
+}
+
+func invalidRequest(data string) *rpcErrors.Error {
+	err := rpcErrors.New(rpcErrors.InvalidRequest)
+	err.Data = data
+
+	return err
+}
+
+func internal(cause error) *rpcErrors.Error {
+	err := rpcErrors.New(rpcErrors.Internal)
+	err.Data = cause.Error()
+
+	return err
+}
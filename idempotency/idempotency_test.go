@@ -0,0 +1,370 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type (
+	chargeMethod struct {
+		calls   int32
+		release chan struct{} // optional, blocks the handling until closed
+		fail    bool
+	}
+	chargeMethodParams struct {
+		Amount int `json:"amount"`
+	}
+)
+
+func (*chargeMethod) GetParamsType() interface{} { return &chargeMethodParams{} }
+func (*chargeMethod) GetName() string            { return "charge" }
+func (m *chargeMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	calls := atomic.AddInt32(&m.calls, 1)
+
+	if m.release != nil {
+		<-m.release
+	}
+
+	if m.fail {
+		return nil, rpcErrors.New(rpcErrors.Internal)
+	}
+
+	return map[string]interface{}{"amount": params.(*chargeMethodParams).Amount, "call": calls}, nil
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(string) (Entry, bool, error) {
+	return Entry{}, false, errors.New("store is unavailable")
+}
+func (failingStore) Set(string, Entry) error { return nil }
+
+func newTestRouter(t *testing.T, method *chargeMethod, i *Idempotency) *rpcRouter.Router {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethod(method))
+
+	router.Use(i.Middleware)
+
+	return router
+}
+
+func params(key string, amount int) map[string]interface{} {
+	p := map[string]interface{}{"amount": float64(amount)}
+
+	if key != "" {
+		p[MetaField] = map[string]interface{}{MetaKeyField: key}
+	}
+
+	return p
+}
+
+// userCtx returns a context with the authenticated principal.
+func userCtx(id string) context.Context {
+	return auth.NewContext(context.Background(), &auth.Principal{ID: id})
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestIdempotency_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{}
+		i      = New(NewMemoryStore())
+		router = newTestRouter(t, method, i)
+		ctx    = userCtx("john")
+	)
+
+	result, err := router.InvokeContext(ctx, "charge", params("key-1", 10))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"amount": 10, "call": 1}`, toJSON(t, result))
+
+	// replay
+	result, err = router.InvokeContext(ctx, "charge", params("key-1", 10))
+	assert.Nil(t, err)
+	assert.IsType(t, map[string]interface{}{}, result) // decoded, so any transport can encode it
+	assert.JSONEq(t, `{"amount": 10, "call": 1}`, toJSON(t, result))
+
+	// the same key with other params
+	_, err = router.InvokeContext(ctx, "charge", params("key-1", 20))
+	assert.Equal(t, int(rpcErrors.InvalidRequest), err.GetCode())
+	assert.Equal(t, "idempotency key was used with other params", err.GetData())
+
+	// other key, the same key of the other principal and calls without key
+	result, _ = router.InvokeContext(ctx, "charge", params("key-2", 10))
+	assert.JSONEq(t, `{"amount": 10, "call": 2}`, toJSON(t, result))
+
+	result, _ = router.InvokeContext(userCtx("jane"), "charge", params("key-1", 10))
+	assert.JSONEq(t, `{"amount": 10, "call": 3}`, toJSON(t, result))
+
+	result, _ = router.InvokeContext(ctx, "charge", params("", 10))
+	assert.JSONEq(t, `{"amount": 10, "call": 4}`, toJSON(t, result))
+
+	result, _ = router.InvokeContext(ctx, "charge", params("", 10))
+	assert.JSONEq(t, `{"amount": 10, "call": 5}`, toJSON(t, result))
+
+	// anonymous calls with keys are rejected by default
+	_, err = router.InvokeContext(context.Background(), "charge", params("key-1", 10))
+	assert.Equal(t, int(rpcErrors.Unauthorized), err.GetCode())
+
+	result, _ = router.InvokeContext(context.Background(), "charge", params("", 10))
+	assert.JSONEq(t, `{"amount": 10, "call": 6}`, toJSON(t, result))
+
+	i.AllowAnonymous = true
+
+	for n := 0; n < 2; n++ {
+		result, err = router.InvokeContext(context.Background(), "charge", params("key-1", 10))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"amount": 10, "call": 7}`, toJSON(t, result))
+	}
+}
+
+func TestIdempotency_MiddlewareHeader(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{}
+		router = newTestRouter(t, method, New(NewMemoryStore()))
+	)
+
+	newCtx := func() (context.Context, *metadata.Metadata) {
+		md := &metadata.Metadata{Header: http.Header{KeyHeader: {"key-1"}}}
+
+		return metadata.NewContext(userCtx("john"), md), md
+	}
+
+	ctx, md := newCtx()
+
+	_, err := router.InvokeContext(ctx, "charge", params("", 10))
+	assert.Nil(t, err)
+	assert.Empty(t, md.ResponseHeader().Get(ReplayedHeader))
+
+	ctx, md = newCtx()
+
+	result, err := router.InvokeContext(ctx, "charge", params("", 10))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"amount": 10, "call": 1}`, toJSON(t, result))
+	assert.Equal(t, "true", md.ResponseHeader().Get(ReplayedHeader))
+}
+
+func TestIdempotency_MiddlewareErrorsAreNotStored(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{fail: true}
+		router = newTestRouter(t, method, New(NewMemoryStore()))
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := router.InvokeContext(userCtx("john"), "charge", params("key-1", 10))
+		assert.Equal(t, int(rpcErrors.Internal), err.GetCode())
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls))
+}
+
+func TestIdempotency_MiddlewareStoreError(t *testing.T) {
+	t.Parallel()
+
+	router := newTestRouter(t, &chargeMethod{}, New(failingStore{}))
+
+	_, err := router.InvokeContext(userCtx("john"), "charge", params("key-1", 10))
+	assert.Equal(t, int(rpcErrors.Internal), err.GetCode())
+	assert.Equal(t, "store is unavailable", err.GetData())
+}
+
+func TestIdempotency_MiddlewareInProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{release: make(chan struct{})}
+		router = newTestRouter(t, method, New(NewMemoryStore()))
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		_, _ = router.InvokeContext(userCtx("john"), "charge", params("key-1", 10))
+	}()
+
+	for atomic.LoadInt32(&method.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := router.InvokeContext(userCtx("john"), "charge", params("key-1", 10))
+	assert.Equal(t, int(rpcErrors.InvalidRequest), err.GetCode())
+	assert.Equal(t, "request with the same idempotency key is in progress", err.GetData())
+
+	close(method.release)
+	<-done
+}
+
+// coalescedChargeMethod opts into the calls without keys coalescing.
+type coalescedChargeMethod struct{ chargeMethod }
+
+func (*coalescedChargeMethod) CoalesceCalls() bool { return true }
+
+// waitInFlight waits until the calls count is reached and the calls are in flight.
+func waitInFlight(i *Idempotency, method *chargeMethod, inFlight int, calls int32) {
+	for {
+		i.mutex.Lock()
+		n := len(i.inFlight)
+		i.mutex.Unlock()
+
+		if n == inFlight && atomic.LoadInt32(&method.calls) == calls {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestIdempotency_MiddlewareCoalesce(t *testing.T) {
+	t.Parallel()
+
+	for name, key := range map[string]string{"with key": "key-1", "without key": ""} {
+		var (
+			method = &coalescedChargeMethod{chargeMethod{release: make(chan struct{})}}
+			i      = New(NewMemoryStore())
+			router = rpcRouter.New()
+			wg     sync.WaitGroup
+		)
+
+		assert.NoError(t, router.RegisterMethod(method))
+		router.Use(i.Middleware)
+
+		i.Coalesce = true
+
+		results := make([]interface{}, 4)
+
+		for n := range results {
+			wg.Add(1)
+
+			go func(n int) {
+				defer wg.Done()
+
+				user := "john"
+				if n == len(results)-1 {
+					user = "jane" // calls of the other principal are not coalesced
+				}
+
+				results[n], _ = router.InvokeContext(userCtx(user), "charge", params(key, 10))
+			}(n)
+		}
+
+		waitInFlight(i, &method.chargeMethod, 2, 2)
+
+		time.Sleep(50 * time.Millisecond) // other calls are waiting for the first one
+		close(method.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&method.calls), name)
+
+		var calls = map[string]int{}
+
+		for _, result := range results {
+			calls[toJSON(t, result)]++
+		}
+
+		assert.Len(t, calls, 2, name)
+		assert.Contains(t, []int{1, 3}, calls[`{"amount":10,"call":1}`], name)
+		assert.Contains(t, []int{1, 3}, calls[`{"amount":10,"call":2}`], name)
+	}
+}
+
+func TestIdempotency_MiddlewareCoalesceOptIn(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{release: make(chan struct{})}
+		i      = New(NewMemoryStore())
+		router = newTestRouter(t, method, i)
+		wg     sync.WaitGroup
+	)
+
+	i.Coalesce = true
+
+	for n := 0; n < 2; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = router.InvokeContext(userCtx("john"), "charge", params("", 10))
+		}()
+	}
+
+	waitInFlight(i, method, 0, 2) // calls without keys are not coalesced for the methods without opting in
+
+	close(method.release)
+	wg.Wait()
+}
+
+func TestIdempotency_MiddlewareCoalesceCancel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = &chargeMethod{release: make(chan struct{})}
+		i      = New(NewMemoryStore())
+		router = newTestRouter(t, method, i)
+		done   = make(chan struct{})
+	)
+
+	i.Coalesce = true
+
+	go func() {
+		defer close(done)
+
+		_, _ = router.InvokeContext(userCtx("john"), "charge", params("key-1", 10))
+	}()
+
+	waitInFlight(i, method, 1, 1)
+
+	ctx, cancel := context.WithTimeout(userCtx("john"), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := router.InvokeContext(ctx, "charge", params("key-1", 10))
+	assert.Equal(t, int(rpcErrors.Timeout), err.GetCode())
+
+	ctx, cancel = context.WithCancel(userCtx("john"))
+	cancel()
+
+	_, err = router.InvokeContext(ctx, "charge", params("key-1", 10))
+	assert.Equal(t, int(rpcErrors.RequestCancelled), err.GetCode())
+
+	close(method.release)
+	<-done
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", Key(context.Background(), nil))
+	assert.Equal(t, "", Key(context.Background(), []interface{}{1}))
+	assert.Equal(t, "", Key(context.Background(), map[string]interface{}{MetaField: "foo"}))
+	assert.Equal(t, "foo", Key(context.Background(), params("foo", 1)))
+
+	ctx := metadata.NewContext(context.Background(), &metadata.Metadata{Header: http.Header{KeyHeader: {"bar"}}})
+
+	assert.Equal(t, "bar", Key(ctx, params("foo", 1)))
+}
@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is a stored call result.
+type Entry struct {
+	Fingerprint string    // params fingerprint
+	Result      []byte    // JSON encoded result
+	ExpiresAt   time.Time // entry expiration time
+}
+
+// Store stores the calls results.
+type Store interface {
+	// Get returns the entry by key (found is false for unknown or expired entries).
+	Get(key string) (entry Entry, found bool, err error)
+
+	// Set saves the entry.
+	Set(key string, entry Entry) error
+}
+
+// DefaultSweepInterval is the default interval of the expired entries removal from the MemoryStore.
+const DefaultSweepInterval = time.Minute
+
+// MemoryStore is an in-memory results store. Expired entries are removed on reading, and all expired entries are
+// removed (on saving) once per SweepInterval.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	entries map[string]Entry
+	sweptAt time.Time
+	now     func() time.Time
+
+	// SweepInterval is the interval of the expired entries removal (DefaultSweepInterval by default).
+	SweepInterval time.Duration
+}
+
+// NewMemoryStore creates new in-memory results store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:       make(map[string]Entry),
+		sweptAt:       time.Now(),
+		now:           time.Now,
+		SweepInterval: DefaultSweepInterval,
+	}
+}
+
+// Get implements Store interface.
+func (s *MemoryStore) Get(key string) (Entry, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	if !s.now().Before(entry.ExpiresAt) {
+		delete(s.entries, key)
+
+		return Entry{}, false, nil
+	}
+
+	return entry, true, nil
+}
+
+// Set implements Store interface.
+func (s *MemoryStore) Set(key string, entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now := s.now(); now.Sub(s.sweptAt) >= s.SweepInterval {
+		s.sweep(now)
+	}
+
+	s.entries[key] = entry
+
+	return nil
+}
+
+// sweep removes expired entries. Must be called under lock.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if !now.Before(entry.ExpiresAt) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.sweptAt = now
+}
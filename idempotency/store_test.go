@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = NewMemoryStore()
+		now   = time.Unix(1600000000, 0)
+	)
+
+	store.now = func() time.Time { return now }
+	store.sweptAt = now
+
+	_, found, err := store.Get("foo")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set("foo", Entry{Fingerprint: "a", Result: []byte(`1`), ExpiresAt: now.Add(time.Second)}))
+	assert.NoError(t, store.Set("bar", Entry{Fingerprint: "b", Result: []byte(`2`), ExpiresAt: now.Add(time.Hour)}))
+	assert.NoError(t, store.Set("baz", Entry{Fingerprint: "c", Result: []byte(`3`), ExpiresAt: now.Add(time.Second)}))
+
+	entry, found, err := store.Get("foo")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "a", entry.Fingerprint)
+	assert.Equal(t, []byte(`1`), entry.Result)
+
+	now = now.Add(time.Second)
+
+	// expired entry is removed on reading
+	_, found, _ = store.Get("foo")
+	assert.False(t, found)
+	assert.Len(t, store.entries, 2)
+
+	// expired entries are not removed on saving until the sweep interval has passed
+	assert.NoError(t, store.Set("qux", Entry{ExpiresAt: now.Add(time.Hour)}))
+	assert.Len(t, store.entries, 3)
+
+	now = now.Add(DefaultSweepInterval)
+
+	assert.NoError(t, store.Set("quux", Entry{ExpiresAt: now.Add(time.Hour)}))
+	assert.Len(t, store.entries, 3) // bar, qux and quux
+
+	_, found, _ = store.Get("bar")
+	assert.True(t, found)
+}
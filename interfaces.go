@@ -1,5 +1,7 @@
 package jsonrpc
 
+import "context"
+
 type (
 
 	// Method used as RPC method handler.
@@ -14,6 +16,15 @@ type (
 		Handle(params interface{}) (interface{}, Error)
 	}
 
+	// ContextMethod is an optional Method interface. When implemented, Router calls HandleContext instead of Handle,
+	// so method can access the context (e.g. client session for the notifications sending).
+	ContextMethod interface {
+		Method
+
+		// HandleContext works like Handle, but accepts a context.
+		HandleContext(ctx context.Context, params interface{}) (interface{}, Error)
+	}
+
 	// Router is used for methods registration and invoking.
 	Router interface {
 		// RegisterMethod make a method registration for later invoking.
@@ -26,6 +37,14 @@ type (
 		Invoke(methodName string, params interface{}) (interface{}, Error)
 	}
 
+	// ContextRouter is a Router that can pass the context into the invoked method (and middlewares).
+	ContextRouter interface {
+		Router
+
+		// InvokeContext works like Invoke, but accepts a context.
+		InvokeContext(ctx context.Context, methodName string, params interface{}) (interface{}, Error)
+	}
+
 	// Error is general RPC error.
 	Error interface {
 		error
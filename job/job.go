@@ -0,0 +1,60 @@
+// Package job implements asynchronous methods: invoking of the async method returns a job immediately, the method is
+// executed in background, and the client polls the job state and result using `job.status`, `job.result` and
+// `job.cancel` methods.
+//
+// The kernel does not register the job methods and middleware itself (it is not aware of the jobs), so the manager
+// must be attached to the router explicitly:
+//
+//	manager := job.NewManager(nil) // in-memory storage
+//	if err := manager.Attach(router); err != nil { // registers job methods and router.Use(manager.Middleware)
+//		// ...
+//	}
+//
+//	kernel := rpcKernel.New(router)
+//	defer manager.Wait()
+package job
+
+import (
+	"time"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// State is a job execution state.
+type State string
+
+// Job states.
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateCompleted State = "completed"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+// Finished checks if the job execution is finished.
+func (s State) Finished() bool {
+	return s == StateCompleted || s == StateFailed || s == StateCancelled
+}
+
+// AsyncMethod is an optional jsonrpc.Method interface. Methods, marked as async, are executed in background.
+type AsyncMethod interface {
+	IsAsync() bool
+}
+
+// Job is an asynchronous method execution.
+type Job struct {
+	ID         string           `json:"id"`
+	Method     string           `json:"method"`
+	State      State            `json:"state"`
+	Result     interface{}      `json:"result,omitempty"`
+	Error      *rpcErrors.Error `json:"error,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty"` // the job is removed from the storage after this time
+}
+
+// Expired checks if the job is expired.
+func (job *Job) Expired(now time.Time) bool {
+	return job.ExpiresAt != nil && !now.Before(*job.ExpiresAt)
+}
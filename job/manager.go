@@ -0,0 +1,181 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// Job methods names.
+const (
+	MethodStatus = "job.status"
+	MethodResult = "job.result"
+	MethodCancel = "job.cancel"
+)
+
+// DefaultResultTTL is the default time of the finished job storing.
+const DefaultResultTTL = time.Minute * 10
+
+// Manager runs async methods and manages jobs.
+type Manager struct {
+	storage Storage
+
+	mutex   sync.Mutex
+	cancels map[string]context.CancelFunc // running jobs
+	wg      sync.WaitGroup
+
+	// ResultTTL is the time of the finished job storing (DefaultResultTTL by default).
+	ResultTTL time.Duration
+}
+
+// NewManager creates new jobs manager. In-memory storage is used, when storage is nil.
+func NewManager(storage Storage) *Manager {
+	if storage == nil {
+		storage = NewMemoryStorage()
+	}
+
+	return &Manager{
+		storage:   storage,
+		cancels:   make(map[string]context.CancelFunc),
+		ResultTTL: DefaultResultTTL,
+	}
+}
+
+// Attach registers the Middleware and the job methods (MethodStatus, MethodResult and MethodCancel) in the router.
+func (manager *Manager) Attach(router *rpcRouter.Router) error {
+	if err := router.RegisterMethods(
+		&statusMethod{manager: manager},
+		&resultMethod{manager: manager},
+		&cancelMethod{manager: manager},
+	); err != nil {
+		return err
+	}
+
+	router.Use(manager.Middleware)
+
+	return nil
+}
+
+// Middleware is a router middleware, that runs async methods (see AsyncMethod) in background. Invoking result is
+// the created job (without result), and the job method is the canonical method name (e.g. `report@v2`).
+func (manager *Manager) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		if method, ok := rpcRouter.MethodFromContext(ctx); ok {
+			if async, ok := method.(AsyncMethod); ok && async.IsAsync() {
+				name, _ := rpcRouter.MethodNameFromContext(ctx) // canonical name (aliases and versions are resolved)
+
+				return manager.start(ctx, name, func(ctx context.Context) (interface{}, jsonrpc.Error) {
+					return next(ctx, methodName, params)
+				})
+			}
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+// Wait waits for all running jobs completion.
+func (manager *Manager) Wait() { manager.wg.Wait() }
+
+// start saves new job and runs it in background.
+func (manager *Manager) start(
+	ctx context.Context,
+	methodName string,
+	run func(ctx context.Context) (interface{}, jsonrpc.Error),
+) (interface{}, jsonrpc.Error) {
+	job := Job{ID: session.NewID(), Method: methodName, State: StatePending, CreatedAt: time.Now()}
+
+	if err := manager.storage.Save(job); err != nil {
+		return nil, internalError(err)
+	}
+
+	// job should not be canceled on the request completion, but it keeps the request context values
+	ctx, cancel := context.WithCancel(detached{ctx})
+
+	manager.mutex.Lock()
+	manager.cancels[job.ID] = cancel
+	manager.mutex.Unlock()
+
+	manager.wg.Add(1)
+
+	go func(job Job) {
+		defer manager.wg.Done()
+		defer func() {
+			manager.mutex.Lock()
+			delete(manager.cancels, job.ID)
+			manager.mutex.Unlock()
+
+			cancel()
+		}()
+
+		job.State = StateRunning
+		_ = manager.storage.Save(job)
+
+		result, err := run(ctx)
+
+		switch {
+		case ctx.Err() != nil:
+			job.State = StateCancelled
+		case err != nil:
+			job.State, job.Error = StateFailed, toError(err)
+		default:
+			job.State, job.Result = StateCompleted, result
+		}
+
+		var (
+			finishedAt = time.Now()
+			expiresAt  = finishedAt.Add(manager.ResultTTL)
+		)
+
+		job.FinishedAt, job.ExpiresAt = &finishedAt, &expiresAt
+
+		_ = manager.storage.Save(job)
+	}(job)
+
+	return job, nil
+}
+
+// Get returns the job.
+func (manager *Manager) Get(id string) (Job, error) { return manager.storage.Get(id) }
+
+// Cancel cancels the running job. It returns false, when the job is not running.
+func (manager *Manager) Cancel(id string) bool {
+	manager.mutex.Lock()
+	cancel, ok := manager.cancels[id]
+	manager.mutex.Unlock()
+
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
+
+// toError converts RPC error interface into error struct.
+func toError(err jsonrpc.Error) *rpcErrors.Error {
+	if e, ok := err.(*rpcErrors.Error); ok {
+		return e
+	}
+
+	return &rpcErrors.Error{Code: rpcErrors.Code(err.GetCode()), Message: err.GetMessage(), Data: err.GetData()}
+}
+
+func internalError(err error) *rpcErrors.Error {
+	rpcErr := rpcErrors.New(rpcErrors.Internal)
+	rpcErr.Data = err.Error()
+
+	return rpcErr
+}
+
+// detached is a context, that is never canceled, but keeps the parent context values.
+type detached struct{ parent context.Context }
+
+func (detached) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detached) Done() <-chan struct{}               { return nil }
+func (detached) Err() error                          { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
@@ -0,0 +1,170 @@
+package job
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func newTestKernel(t *testing.T) (*rpcKernel.Kernel, *Manager, *reportMethod) {
+	var (
+		router  = rpcRouter.New()
+		manager = NewManager(nil)
+		method  = &reportMethod{release: make(chan struct{})}
+	)
+
+	assert.NoError(t, router.RegisterMethods(method, &syncMethod{}))
+	assert.NoError(t, manager.Attach(router))
+
+	return rpcKernel.New(router), manager, method
+}
+
+// call invokes the method and returns decoded response.
+func call(t *testing.T, kernel *rpcKernel.Kernel, request string) map[string]interface{} {
+	var response map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(kernel.HandleJSONRequest([]byte(request)), &response))
+
+	return response
+}
+
+// startJob invokes async method and returns the job ID.
+func startJob(t *testing.T, kernel *rpcKernel.Kernel, params string) string {
+	response := call(t, kernel, `{"jsonrpc": "2.0", "method": "report", "params": `+params+`, "id": 1}`)
+
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, "report", result["method"])
+	assert.Contains(t, []interface{}{"pending", "running"}, result["state"])
+
+	return result["id"].(string)
+}
+
+// errorCode returns the response error code.
+func errorCode(response map[string]interface{}) float64 {
+	return response["error"].(map[string]interface{})["code"].(float64)
+}
+
+// state returns the job state from the job.status method response.
+func state(response map[string]interface{}) string {
+	return response["result"].(map[string]interface{})["state"].(string)
+}
+
+func jobRequest(method, id string) string {
+	return `{"jsonrpc": "2.0", "method": "` + method + `", "params": {"id": "` + id + `"}, "id": 1}`
+}
+
+func TestManager_CompletedJob(t *testing.T) {
+	t.Parallel()
+
+	kernel, manager, method := newTestKernel(t)
+	id := startJob(t, kernel, `{}`)
+
+	assert.Equal(t, float64(CodeNotFinished), errorCode(call(t, kernel, jobRequest(MethodResult, id))))
+
+	close(method.release)
+	manager.Wait()
+
+	status := call(t, kernel, jobRequest(MethodStatus, id))["result"].(map[string]interface{})
+	assert.Equal(t, "completed", status["state"])
+	assert.NotContains(t, status, "result")
+	assert.Contains(t, status, "finished_at")
+	assert.Contains(t, status, "expires_at")
+
+	assert.Equal(t, "report", call(t, kernel, jobRequest(MethodResult, id))["result"])
+	assert.Equal(t, false, call(t, kernel, jobRequest(MethodCancel, id))["result"])
+}
+
+func TestManager_FailedJob(t *testing.T) {
+	t.Parallel()
+
+	kernel, manager, method := newTestKernel(t)
+	id := startJob(t, kernel, `{"fail": true}`)
+
+	close(method.release)
+	manager.Wait()
+
+	assert.Equal(t, "failed", state(call(t, kernel, jobRequest(MethodStatus, id))))
+	assert.Equal(t,
+		map[string]interface{}{"code": float64(1), "message": "failed"},
+		call(t, kernel, jobRequest(MethodResult, id))["error"],
+	)
+}
+
+func TestManager_CancelledJob(t *testing.T) {
+	t.Parallel()
+
+	kernel, manager, _ := newTestKernel(t)
+	id := startJob(t, kernel, `{}`)
+
+	assert.Equal(t, true, call(t, kernel, jobRequest(MethodCancel, id))["result"])
+	manager.Wait()
+
+	assert.Equal(t, "cancelled", state(call(t, kernel, jobRequest(MethodStatus, id))))
+	assert.Equal(t, float64(-32800), errorCode(call(t, kernel, jobRequest(MethodResult, id))))
+}
+
+func TestManager_ResultTTL(t *testing.T) {
+	t.Parallel()
+
+	kernel, manager, method := newTestKernel(t)
+	manager.ResultTTL = time.Millisecond
+
+	id := startJob(t, kernel, `{}`)
+
+	close(method.release)
+	manager.Wait()
+
+	time.Sleep(time.Millisecond * 2)
+
+	_, err := manager.Get(id)
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, float64(CodeNotFound), errorCode(call(t, kernel, jobRequest(MethodStatus, id))))
+}
+
+func TestManager_SyncMethod(t *testing.T) {
+	t.Parallel()
+
+	kernel, _, _ := newTestKernel(t)
+
+	assert.Equal(t, "sync", call(t, kernel, `{"jsonrpc": "2.0", "method": "sync", "id": 1}`)["result"])
+}
+
+func TestManager_WrongParams(t *testing.T) {
+	t.Parallel()
+
+	kernel, _, _ := newTestKernel(t)
+
+	assert.Equal(t, float64(-32602), errorCode(call(t, kernel, jobRequest(MethodStatus, ""))))
+}
+
+func TestManager_AttachTwice(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+	router.DisallowDuplicates = true
+
+	assert.NoError(t, NewManager(nil).Attach(router))
+	assert.Error(t, NewManager(nil).Attach(router))
+}
+
+func TestManager_CanonicalMethodName(t *testing.T) {
+	t.Parallel()
+
+	kernel, manager, method := newTestKernel(t)
+	router := kernel.Router().(*rpcRouter.Router)
+
+	assert.NoError(t, router.RegisterAlias("report.alias", "report"))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", &reportMethod{release: method.release}))
+
+	for name, want := range map[string]string{"report.alias": "report", "report@v2": "report@v2"} {
+		response := call(t, kernel, `{"jsonrpc": "2.0", "method": "`+name+`", "params": {}, "id": 1}`)
+		assert.Equal(t, want, response["result"].(map[string]interface{})["method"], name)
+	}
+
+	close(method.release)
+	manager.Wait()
+}
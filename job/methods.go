@@ -0,0 +1,88 @@
+package job
+
+import (
+	"errors"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// Job methods error codes (server error range).
+const (
+	CodeNotFound    rpcErrors.Code = -32010
+	CodeNotFinished rpcErrors.Code = -32011
+)
+
+type methodParams struct {
+	ID string `json:"id"`
+}
+
+// Validate implements jsonrpc.Validator interface.
+func (p *methodParams) Validate() error {
+	if p.ID == "" {
+		return errors.New("job ID is required")
+	}
+
+	return nil
+}
+
+func (manager *Manager) get(params interface{}) (Job, jsonrpc.Error) {
+	job, err := manager.storage.Get(params.(*methodParams).ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Job{}, &rpcErrors.Error{Code: CodeNotFound, Message: "Job not found"}
+		}
+
+		return Job{}, internalError(err)
+	}
+
+	return job, nil
+}
+
+// statusMethod returns the job without result.
+type statusMethod struct{ manager *Manager }
+
+func (*statusMethod) GetName() string            { return MethodStatus }
+func (*statusMethod) GetParamsType() interface{} { return &methodParams{} }
+func (m *statusMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	job, err := m.manager.get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Result = nil
+
+	return job, nil
+}
+
+// resultMethod returns the finished job result (or error).
+type resultMethod struct{ manager *Manager }
+
+func (*resultMethod) GetName() string            { return MethodResult }
+func (*resultMethod) GetParamsType() interface{} { return &methodParams{} }
+func (m *resultMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	job, err := m.manager.get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.State {
+	case StateCompleted:
+		return job.Result, nil
+	case StateFailed:
+		return nil, job.Error
+	case StateCancelled:
+		return nil, rpcErrors.New(rpcErrors.RequestCancelled)
+	}
+
+	return nil, &rpcErrors.Error{Code: CodeNotFinished, Message: "Job is not finished", Data: job.State}
+}
+
+// cancelMethod cancels the running job.
+type cancelMethod struct{ manager *Manager }
+
+func (*cancelMethod) GetName() string            { return MethodCancel }
+func (*cancelMethod) GetParamsType() interface{} { return &methodParams{} }
+func (m *cancelMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	return m.manager.Cancel(params.(*methodParams).ID), nil
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+type (
+	reportMethod struct {
+		release chan struct{}
+	}
+	reportMethodParams struct {
+		Fail bool `json:"fail"`
+	}
+)
+
+func (*reportMethod) GetParamsType() interface{} { return &reportMethodParams{} }
+func (*reportMethod) GetName() string            { return "report" }
+func (*reportMethod) IsAsync() bool              { return true }
+func (m *reportMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	return m.HandleContext(context.Background(), params)
+}
+
+func (m *reportMethod) HandleContext(ctx context.Context, params interface{}) (interface{}, jsonrpc.Error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	case <-m.release:
+	}
+
+	if params.(*reportMethodParams).Fail {
+		return nil, &rpcErrors.Error{Code: 1, Message: "failed"}
+	}
+
+	return "report", nil
+}
+
+type syncMethod struct{}
+
+func (*syncMethod) GetParamsType() interface{}                        { return nil }
+func (*syncMethod) GetName() string                                   { return "sync" }
+func (*syncMethod) IsAsync() bool                                     { return false }
+func (*syncMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return "sync", nil }
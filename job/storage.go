@@ -0,0 +1,100 @@
+package job
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by the storage, when the job was not found (or expired).
+var ErrNotFound = errors.New("jsonrpc: job not found")
+
+// Storage stores jobs.
+type Storage interface {
+	// Save creates or updates the job.
+	Save(job Job) error
+
+	// Get returns the job by ID (ErrNotFound is returned for unknown or expired jobs).
+	Get(id string) (Job, error)
+
+	// Delete removes the job.
+	Delete(id string) error
+}
+
+// DefaultSweepInterval is the default interval of the expired jobs removal from the MemoryStorage.
+const DefaultSweepInterval = time.Minute
+
+// MemoryStorage is an in-memory jobs storage. Expired jobs are removed on reading, and all expired jobs are removed
+// (on saving) once per SweepInterval.
+type MemoryStorage struct {
+	mutex   sync.Mutex
+	jobs    map[string]Job
+	sweptAt time.Time
+	now     func() time.Time
+
+	// SweepInterval is the interval of the expired jobs removal (DefaultSweepInterval by default).
+	SweepInterval time.Duration
+}
+
+// NewMemoryStorage creates new in-memory jobs storage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		jobs:          make(map[string]Job),
+		sweptAt:       time.Now(),
+		now:           time.Now,
+		SweepInterval: DefaultSweepInterval,
+	}
+}
+
+// Save implements Storage interface.
+func (s *MemoryStorage) Save(job Job) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now := s.now(); now.Sub(s.sweptAt) >= s.SweepInterval {
+		s.sweep(now)
+	}
+
+	s.jobs[job.ID] = job
+
+	return nil
+}
+
+// Get implements Storage interface.
+func (s *MemoryStorage) Get(id string) (Job, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+
+	if job.Expired(s.now()) {
+		delete(s.jobs, id)
+
+		return Job{}, ErrNotFound
+	}
+
+	return job, nil
+}
+
+// Delete implements Storage interface.
+func (s *MemoryStorage) Delete(id string) error {
+	s.mutex.Lock()
+	delete(s.jobs, id)
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// sweep removes expired jobs. Must be called under lock.
+func (s *MemoryStorage) sweep(now time.Time) {
+	for id, job := range s.jobs {
+		if job.Expired(now) {
+			delete(s.jobs, id)
+		}
+	}
+
+	s.sweptAt = now
+}
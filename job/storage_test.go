@@ -0,0 +1,93 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage() (*MemoryStorage, *time.Time) {
+	var (
+		storage = NewMemoryStorage()
+		now     = time.Unix(1600000000, 0)
+	)
+
+	storage.now = func() time.Time { return now }
+	storage.sweptAt = now
+
+	return storage, &now
+}
+
+func TestMemoryStorage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storage, now = newTestStorage()
+		expiresAt    = now.Add(time.Second)
+	)
+
+	_, err := storage.Get("foo")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.NoError(t, storage.Save(Job{ID: "foo", State: StatePending}))
+
+	job, err := storage.Get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, job.State)
+
+	assert.NoError(t, storage.Save(Job{ID: "foo", State: StateCompleted, ExpiresAt: &expiresAt}))
+
+	_, err = storage.Get("foo")
+	assert.NoError(t, err)
+
+	*now = now.Add(time.Second)
+
+	_, err = storage.Get("foo")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NotContains(t, storage.jobs, "foo") // expired jobs are removed on reading
+
+	assert.NoError(t, storage.Save(Job{ID: "bar"}))
+	assert.NoError(t, storage.Delete("bar"))
+
+	_, err = storage.Get("bar")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMemoryStorage_Sweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		storage, now = newTestStorage()
+		expiresAt    = now.Add(time.Second)
+	)
+
+	assert.NoError(t, storage.Save(Job{ID: "foo", ExpiresAt: &expiresAt}))
+	assert.NoError(t, storage.Save(Job{ID: "bar"}))
+
+	*now = now.Add(time.Second)
+
+	assert.NoError(t, storage.Save(Job{ID: "baz"}))
+	assert.Len(t, storage.jobs, 3) // expired jobs are not removed before the sweep interval
+
+	*now = now.Add(DefaultSweepInterval)
+
+	assert.NoError(t, storage.Save(Job{ID: "baz"}))
+	assert.Len(t, storage.jobs, 2)
+	assert.NotContains(t, storage.jobs, "foo")
+}
+
+func TestState_Finished(t *testing.T) {
+	t.Parallel()
+
+	for state, want := range map[State]bool{
+		StatePending:   false,
+		StateRunning:   false,
+		StateCompleted: true,
+		StateFailed:    true,
+		StateCancelled: true,
+	} {
+		assert.Equal(t, want, state.Finished(), state)
+	}
+}
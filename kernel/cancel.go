@@ -0,0 +1,91 @@
+package kernel
+
+import (
+	"context"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// CancelRequestMethod is a built-in notification (LSP-compatible) for the in-flight request canceling. Params are
+// `{"id": <request ID>}`. Only requests of the same session (persistent connection) can be canceled.
+const CancelRequestMethod = "$/cancelRequest"
+
+type inFlightRequest struct {
+	cancel context.CancelFunc
+}
+
+// track registers the request with ID as in-flight request (when session is attached to the context). Returned
+// context is canceled on CancelRequestMethod call, and done func must be called on request processing completion.
+func (kernel *Kernel) track(ctx context.Context, id interface{}) (_ context.Context, done func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	key, ok := kernel.inFlightKey(ctx, id)
+	if !ok {
+		return ctx, cancel
+	}
+
+	entry := &inFlightRequest{cancel: cancel}
+
+	kernel.inFlightMutex.Lock()
+	kernel.inFlight[key] = entry
+	kernel.inFlightMutex.Unlock()
+
+	return ctx, func() {
+		kernel.inFlightMutex.Lock()
+		if kernel.inFlight[key] == entry { // request with the same ID can be registered again
+			delete(kernel.inFlight, key)
+		}
+		kernel.inFlightMutex.Unlock()
+
+		cancel()
+	}
+}
+
+// cancelRequest processes CancelRequestMethod call.
+func (kernel *Kernel) cancelRequest(
+	ctx context.Context,
+	request rpcRequest.Request,
+	version string,
+) *rpcResponse.Response {
+	params, _ := request.Params.(map[string]interface{})
+
+	id, exists := params["id"]
+	if !exists || id == nil {
+		err := rpcErrors.New(rpcErrors.InvalidParams)
+		err.Data = "request ID is required"
+
+		return &rpcResponse.Response{Version: version, Error: err, ID: request.ID}
+	}
+
+	if key, ok := kernel.inFlightKey(ctx, id); ok {
+		kernel.inFlightMutex.Lock()
+		entry, found := kernel.inFlight[key]
+		kernel.inFlightMutex.Unlock()
+
+		if found {
+			entry.cancel()
+		}
+	}
+
+	var result interface{}
+
+	return &rpcResponse.Response{Version: version, Result: &result, ID: request.ID}
+}
+
+// inFlightKey returns in-flight requests registry key (session ID + request ID).
+func (kernel *Kernel) inFlightKey(ctx context.Context, id interface{}) (string, bool) {
+	s, ok := session.FromContext(ctx)
+	if !ok || id == nil {
+		return "", false
+	}
+
+	data, err := kernel.json.Marshal(id)
+	if err != nil {
+		return "", false
+	}
+
+	return s.ID() + "\x00" + string(data), true
+}
@@ -0,0 +1,108 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+func newCancelTestKernel(t *testing.T) (*Kernel, *blockingMethod) {
+	router, method := rpcRouter.New(), newBlockingMethod()
+
+	assert.NoError(t, router.RegisterMethod(method))
+
+	return New(router), method
+}
+
+func TestKernel_CancelRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel, method = newCancelTestKernel(t)
+		ctx            = session.WithSession(context.Background(), fakeSession{id: "1"})
+		result         = make(chan []byte, 1)
+	)
+
+	go func() {
+		result <- kernel.HandleJSONRequestContext(ctx, []byte(`{"jsonrpc": "2.0", "method": "block", "id": 1}`))
+	}()
+
+	<-method.started
+
+	// other session cannot cancel the request
+	assert.Nil(t, kernel.HandleJSONRequestContext(
+		session.WithSession(context.Background(), fakeSession{id: "2"}),
+		[]byte(`{"jsonrpc": "2.0", "method": "$/cancelRequest", "params": {"id": 1}}`),
+	))
+
+	// wrong ID
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": null, "id": 2}`, string(kernel.HandleJSONRequestContext(
+		ctx, []byte(`{"jsonrpc": "2.0", "method": "$/cancelRequest", "params": {"id": "1"}, "id": 2}`),
+	)))
+
+	assert.Nil(t, kernel.HandleJSONRequestContext(
+		ctx, []byte(`{"jsonrpc": "2.0", "method": "$/cancelRequest", "params": {"id": 1}}`),
+	))
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32800, "message": "Request cancelled"}, "id": 1}`,
+		string(<-result),
+	)
+
+	kernel.inFlightMutex.Lock()
+	assert.Empty(t, kernel.inFlight)
+	kernel.inFlightMutex.Unlock()
+}
+
+func TestKernel_CancelRequestWithoutID(t *testing.T) {
+	t.Parallel()
+
+	kernel, _ := newCancelTestKernel(t)
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {
+			"code": -32602, "message": "Invalid params", "data": "request ID is required"
+		}, "id": 1}`,
+		string(kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "$/cancelRequest", "id": 1}`))),
+	)
+}
+
+func TestKernel_RequestCanceledByParentContext(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel, method = newCancelTestKernel(t)
+		ctx, cancel    = context.WithCancel(context.Background()) // e.g. HTTP client disconnection
+		result         = make(chan []byte, 1)
+	)
+
+	go func() {
+		result <- kernel.HandleJSONRequestContext(ctx, []byte(`{"jsonrpc": "2.0", "method": "block", "id": 1}`))
+	}()
+
+	<-method.started
+	cancel()
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32800, "message": "Request cancelled"}, "id": 1}`,
+		string(<-result),
+	)
+}
+
+func TestKernel_NotCanceledRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel, method = newCancelTestKernel(t)
+		ctx            = session.WithSession(context.Background(), fakeSession{id: "1"})
+	)
+
+	close(method.release)
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": "released", "id": 1}`, string(kernel.HandleJSONRequestContext(
+		ctx, []byte(`{"jsonrpc": "2.0", "method": "block", "id": 1}`),
+	)))
+}
@@ -0,0 +1,62 @@
+package kernel
+
+import (
+	"context"
+
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+)
+
+type (
+	// Payload describes an incoming payload (single request or batch).
+	Payload struct {
+		Size       int   // payload size in bytes
+		Batch      bool  // payload is a batch
+		Requests   int   // requests count (batch size)
+		ParseError error // payload parsing error (if any)
+	}
+
+	// PayloadHook is called before the payload processing. It can return a new context (it will be passed into the
+	// requests processing), and a func, that is called after the payload processing (can be nil).
+	PayloadHook func(ctx context.Context, payload Payload) (context.Context, func())
+)
+
+// Hook appends the payload hooks (e.g. for batch size metrics or tracing). Hooks are executed in the order of
+// registration, and "after" funcs - in the reverse order.
+func (kernel *Kernel) Hook(hooks ...PayloadHook) {
+	kernel.middlewaresMutex.Lock()
+	kernel.hooks = append(kernel.hooks, hooks...)
+	kernel.middlewaresMutex.Unlock()
+}
+
+// runHooks executes registered payload hooks and returns a func, that executes hooks "after" funcs.
+func (kernel *Kernel) runHooks(ctx context.Context, payload Payload) (context.Context, func()) {
+	kernel.middlewaresMutex.RLock()
+	var hooks = kernel.hooks
+	kernel.middlewaresMutex.RUnlock()
+
+	var after = make([]func(), 0, len(hooks))
+
+	for _, hook := range hooks {
+		var fn func()
+
+		if ctx, fn = hook(ctx, payload); fn != nil {
+			after = append(after, fn)
+		}
+	}
+
+	return ctx, func() {
+		for i := len(after) - 1; i >= 0; i-- {
+			after[i]()
+		}
+	}
+}
+
+func newPayload(in []byte, requests *[]rpcRequest.Request, isBatch bool, parseErr error) Payload {
+	var payload = Payload{Size: len(in), Batch: isBatch, ParseError: parseErr}
+
+	if parseErr == nil && requests != nil {
+		payload.Requests = len(*requests)
+	}
+
+	return payload
+}
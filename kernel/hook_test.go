@@ -0,0 +1,76 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func TestKernel_Hook(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		giveJSON      string
+		wantPayload   Payload
+		wantParseFail bool
+	}{
+		{
+			name:        "single request",
+			giveJSON:    `{"jsonrpc": "2.0", "method": "foo", "id": 1}`,
+			wantPayload: Payload{Requests: 1},
+		},
+		{
+			name:        "batch",
+			giveJSON:    `[{"jsonrpc": "2.0", "method": "foo", "id": 1}, {"jsonrpc": "2.0", "method": "bar"}]`,
+			wantPayload: Payload{Batch: true, Requests: 2},
+		},
+		{
+			name:          "parse error",
+			giveJSON:      `{`,
+			wantParseFail: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			type ctxKey struct{}
+
+			var (
+				kernel = New(rpcRouter.New())
+				calls  []string
+				got    Payload
+			)
+
+			kernel.Hook(
+				func(ctx context.Context, payload Payload) (context.Context, func()) {
+					got = payload
+					calls = append(calls, "first")
+
+					return context.WithValue(ctx, ctxKey{}, "foo"), func() { calls = append(calls, "first after") }
+				},
+				func(ctx context.Context, _ Payload) (context.Context, func()) {
+					calls = append(calls, "second:"+ctx.Value(ctxKey{}).(string))
+
+					return ctx, nil
+				},
+			)
+
+			kernel.HandleJSONRequest([]byte(tt.giveJSON))
+
+			assert.Equal(t, []string{"first", "second:foo", "first after"}, calls)
+			assert.Equal(t, len(tt.giveJSON), got.Size)
+
+			got.Size = 0
+
+			if tt.wantParseFail {
+				assert.Error(t, got.ParseError)
+				assert.Equal(t, 0, got.Requests)
+			} else {
+				assert.Equal(t, tt.wantPayload, got)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package kernel
 
 import (
+	"context"
 	"math"
 	"sync"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/tarampampam/go-jsonrpc"
@@ -19,6 +21,26 @@ type Kernel struct {
 	router               jsonrpc.Router
 	json                 jsoniter.API
 	InvokingErrorHandler ErrorHandler
+
+	// AllowLegacyVersions allows JsonRPC 1.0 and 1.1 requests processing (responses will be in the same version).
+	AllowLegacyVersions bool
+
+	// DefaultTimeout limits methods execution time (zero means "without limit").
+	DefaultTimeout time.Duration
+
+	// MethodTimeouts overrides DefaultTimeout for the methods (by method canonical name, when the router resolves it,
+	// e.g. `users.fetch@v2` or `users.fetch` for all versions).
+	MethodTimeouts map[string]time.Duration
+
+	// Limits protects the kernel from abusive payloads (payloads are not limited by default).
+	Limits Limits
+
+	middlewaresMutex sync.RWMutex
+	middlewares      []Middleware
+	hooks            []PayloadHook
+
+	inFlightMutex sync.Mutex
+	inFlight      map[string]*inFlightRequest // requests, that can be canceled using CancelRequestMethod
 }
 
 // DefaultErrorHandler just proxy error interface into error struct.
@@ -40,19 +62,32 @@ func New(router jsonrpc.Router) *Kernel {
 		router:               router,
 		json:                 jsoniter.ConfigFastest,
 		InvokingErrorHandler: DefaultErrorHandler,
+		inFlight:             make(map[string]*inFlightRequest),
 	}
 }
 
+// Router returns the router, used by the kernel.
+func (kernel *Kernel) Router() jsonrpc.Router { return kernel.router }
+
 // HandleJSONRequest accepts json request and returns processed json response.
 func (kernel *Kernel) HandleJSONRequest(inJSON []byte) []byte {
+	return kernel.HandleJSONRequestContext(context.Background(), inJSON)
+}
+
+// HandleJSONRequestContext works like HandleJSONRequest, but passes the context into the router (when it
+// implements jsonrpc.ContextRouter interface).
+func (kernel *Kernel) HandleJSONRequestContext(ctx context.Context, inJSON []byte) []byte {
 	responses := rpcResponse.NewResponses()
 
 	// parse incoming json string into requests
 	requests, isBatch, parseErr := kernel.ParseJSONToRequests(inJSON)
 
+	ctx, finish := kernel.runHooks(ctx, newPayload(inJSON, requests, isBatch, parseErr))
+	defer finish()
+
 	// and in parsing fails - push error about this into responses stack
 	if parseErr != nil {
-		responses.Add(rpcResponse.Response{Version: jsonrpc.Version, Error: rpcErrors.New(rpcErrors.Parse)})
+		responses.Add(rpcResponse.Response{Version: jsonrpc.Version, Error: parseError(parseErr)})
 
 		isBatch = false
 	} else {
@@ -65,17 +100,23 @@ func (kernel *Kernel) HandleJSONRequest(inJSON []byte) []byte {
 			wg := sync.WaitGroup{}
 
 			// loop over all passed requests
-			for _, request := range *requests {
+			for i, request := range *requests {
 				wg.Add(1)
 
+				var reqCtx = ctx
+
+				if isBatch {
+					reqCtx = withBatch(ctx, batch{index: i, size: len(*requests)})
+				}
+
 				// execute request processing using goroutines
-				go func(request rpcRequest.Request) {
-					if response := kernel.processRequest(request); response != nil {
+				go func(ctx context.Context, request rpcRequest.Request) {
+					if response := kernel.processRequest(ctx, request); response != nil {
 						responses.Add(*response)
 					}
 
 					wg.Done()
-				}(request)
+				}(reqCtx, request)
 			}
 
 			wg.Wait()
@@ -85,25 +126,58 @@ func (kernel *Kernel) HandleJSONRequest(inJSON []byte) []byte {
 	var result []byte
 
 	if isBatch {
-		result, _ = kernel.json.Marshal(responses.Items)
+		items := make([]interface{}, len(responses.Items))
+
+		for i, item := range responses.Items {
+			items[i] = item.Versioned()
+		}
+
+		result, _ = kernel.json.Marshal(items)
 	} else if len(responses.Items) == 1 { // @todo: ` && responses.Items[0].Result != nil` ???
 		// if request was NOT batch - only one response should be in responses stack
-		result, _ = kernel.json.Marshal(responses.Items[0])
+		result, _ = kernel.json.Marshal(responses.Items[0].Versioned())
 	}
 
 	return result
 }
 
-// processRequest accepts PRC request, invoke it (if it can be invoked) and return response on success or error.
-// Notifications will be processed without response returning.
-func (kernel *Kernel) processRequest(request rpcRequest.Request) *rpcResponse.Response {
+// processRequest accepts PRC request, passes it through the middlewares chain and returns response on success or
+// error. Notifications will be processed without response returning.
+func (kernel *Kernel) processRequest(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+	response := kernel.requestHandler()(ctx, request)
+
+	// valid request without ID is a notification
+	if _, validationErr := kernel.validate(request); validationErr == nil && request.ID == nil {
+		return nil
+	}
+
+	return response
+}
+
+// validate validates the request and returns the response version.
+func (kernel *Kernel) validate(request rpcRequest.Request) (version string, err error) {
+	if kernel.AllowLegacyVersions && request.IsLegacy() {
+		if request.Version == jsonrpc.Version11 {
+			return jsonrpc.Version11, request.ValidateLegacy()
+		}
+
+		return jsonrpc.Version10, request.ValidateLegacy()
+	}
+
+	return jsonrpc.Version, request.Validate()
+}
+
+// handleRequest invokes the request (if it can be invoked) and returns response (for notifications too).
+func (kernel *Kernel) handleRequest(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+	version, validationErr := kernel.validate(request)
+
 	// for valid request we do
-	if validationErr := request.Validate(); validationErr != nil {
+	if validationErr != nil {
 		err := rpcErrors.New(rpcErrors.InvalidRequest)
 		err.Data = validationErr.Error()
 
 		invalidRequestErr := rpcResponse.Response{
-			Version: jsonrpc.Version,
+			Version: version,
 			Error:   err,
 		}
 
@@ -115,30 +189,53 @@ func (kernel *Kernel) processRequest(request rpcRequest.Request) *rpcResponse.Re
 		return &invalidRequestErr
 	}
 
+	if request.Method == CancelRequestMethod {
+		return kernel.cancelRequest(ctx, request, version)
+	}
+
+	ctx, done := kernel.track(ctx, request.ID)
+	defer done()
+
+	if timeout := kernel.timeout(ctx, request.Method); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	// method invoking with error handling
-	result, invokeErr := kernel.router.Invoke(request.Method, request.Params)
-
-	// if request has ID (it was NOT notification)
-	if request.ID != nil {
-		// and error was not occurred
-		if invokeErr == nil {
-			// push method result into responses stack (result as pointer is important)
-			return &rpcResponse.Response{Version: jsonrpc.Version, Result: &result, ID: request.ID}
-		}
+	result, invokeErr := kernel.execute(ctx, request.Method, request.Params)
 
-		// on error - push error response into responses stack
-		return &rpcResponse.Response{
-			Version: jsonrpc.Version,
-			Error:   kernel.InvokingErrorHandler(invokeErr),
-			ID:      request.ID,
-		}
+	// and error was not occurred
+	if invokeErr == nil {
+		// push method result into responses stack (result as pointer is important)
+		return &rpcResponse.Response{Version: version, Result: &result, ID: request.ID}
 	}
 
-	return nil
+	// on error - push error response into responses stack
+	return &rpcResponse.Response{
+		Version: version,
+		Error:   kernel.InvokingErrorHandler(invokeErr),
+		ID:      request.ID,
+	}
+}
+
+// invoke calls the router with context passing, if router supports it.
+func (kernel *Kernel) invoke(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+	if router, ok := kernel.router.(jsonrpc.ContextRouter); ok {
+		return router.InvokeContext(ctx, methodName, params)
+	}
+
+	return kernel.router.Invoke(methodName, params)
 }
 
-// ParseJSONToRequests accepts json string and convert it into requests slice.
+// ParseJSONToRequests accepts json string and convert it into requests slice. Payload is checked against the kernel
+// limits before decoding (*LimitError is returned, when it exceeds them).
 func (kernel *Kernel) ParseJSONToRequests(inJSON []byte) (requests *[]rpcRequest.Request, isBatch bool, err error) {
+	if err = kernel.Limits.check(inJSON); err != nil {
+		return
+	}
+
 	var (
 		batch  = make([]interface{}, 0)
 		single interface{}
@@ -180,6 +277,10 @@ func (kernel *Kernel) parseRawRequest(in interface{}) rpcRequest.Request {
 			if version, ok := property.(string); ok {
 				result.Version = version
 			}
+		} else if property, propOk := input["version"]; propOk { // JsonRPC 1.1
+			if version, ok := property.(string); ok {
+				result.Version = version
+			}
 		}
 
 		if property, propOk := input["method"]; propOk {
@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -341,3 +342,118 @@ func TestKernel_HandleJSONRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestKernel_Router(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+
+	assert.Same(t, router, New(router).Router())
+}
+
+func TestKernel_HandleJSONRequestContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	router := rpcRouter.New()
+	router.Use(func(next rpcRouter.Handler) rpcRouter.Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			return ctx.Value(ctxKey{}), nil
+		}
+	})
+
+	result := New(router).HandleJSONRequestContext(
+		context.WithValue(context.Background(), ctxKey{}, "foo"),
+		[]byte(`{"jsonrpc": "2.0", "method": "any", "id": 1}`),
+	)
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": "foo", "id": 1}`, string(result))
+}
+
+func TestKernel_HandleJSONRequestLegacyVersions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		giveJSON       string
+		giveAllow      bool
+		wantResultJSON string
+	}{
+		{
+			name:     "1.0 request is not allowed by default",
+			giveJSON: `{"method": "subtract", "params": [42, 23], "id": 1}`,
+			wantResultJSON: `{"jsonrpc": "2.0", "error": {
+								"code": -32600, "message": "Invalid Request", "data": "wrong version"
+							}, "id": 1}`,
+		},
+		{
+			name:           "1.0 request",
+			giveJSON:       `{"method": "subtract", "params": [42, 23], "id": 1}`,
+			giveAllow:      true,
+			wantResultJSON: `{"result": 19, "error": null, "id": 1}`,
+		},
+		{
+			name:           "1.0 request with error",
+			giveJSON:       `{"method": "unknown", "params": [], "id": "foo"}`,
+			giveAllow:      true,
+			wantResultJSON: `{"result": null, "error": {"code": -32601, "message": "Method not found"}, "id": "foo"}`,
+		},
+		{
+			name:      "1.0 request with params as an object",
+			giveJSON:  `{"method": "subtract", "params": {"foo": 1}, "id": 1}`,
+			giveAllow: true,
+			wantResultJSON: `{"result": null, "error": {
+								"code": -32600, "message": "Invalid Request", "data": "wrong params type"
+							}, "id": 1}`,
+		},
+		{
+			name:      "1.0 notification",
+			giveJSON:  `{"method": "subtract", "params": [42, 23], "id": null}`,
+			giveAllow: true,
+		},
+		{
+			name:           "1.1 request",
+			giveJSON:       `{"version": "1.1", "method": "subtract_object", "params": {"first": 42, "second": 23}, "id": 1}`,
+			giveAllow:      true,
+			wantResultJSON: `{"version": "1.1", "result": {"result": 19}, "id": 1}`,
+		},
+		{
+			name:           "2.0 request",
+			giveJSON:       `{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1}`,
+			giveAllow:      true,
+			wantResultJSON: `{"jsonrpc": "2.0", "result": 19, "id": 1}`,
+		},
+		{
+			name: "mixed batch",
+			giveJSON: `[
+				{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1},
+				{"method": "subtract", "params": [42, 23], "id": 2}
+			]`,
+			giveAllow: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			router := rpcRouter.New()
+
+			assert.NoError(t, router.RegisterMethods(&subtractMethod{}, &subtractObjectMethod{}))
+
+			kernel := New(router)
+			kernel.AllowLegacyVersions = tt.giveAllow
+
+			result := kernel.HandleJSONRequest([]byte(tt.giveJSON))
+
+			switch {
+			case tt.wantResultJSON != "":
+				assert.JSONEq(t, tt.wantResultJSON, string(result))
+			case tt.giveJSON[0] == '[':
+				assert.Contains(t, string(result), `{"jsonrpc":"2.0","result":19,"id":1}`)
+				assert.Contains(t, string(result), `{"result":19,"error":null,"id":2}`)
+			default:
+				assert.Empty(t, result)
+			}
+		})
+	}
+}
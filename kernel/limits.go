@@ -0,0 +1,158 @@
+package kernel
+
+import (
+	"errors"
+	"strconv"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// Limits protects the kernel from abusive payloads. Limits are checked before the payload decoding (zero value
+// means "without limit").
+type Limits struct {
+	MaxPayloadBytes int // maximal payload size in bytes
+	MaxDepth        int // maximal arrays and objects nesting depth (batch array is counted too)
+	MaxStringLength int // maximal string (and object key) length in bytes (as encoded, with escape sequences)
+	MaxElements     int // maximal elements count of a single array or object
+	MaxBatchLength  int // maximal requests count in a batch
+}
+
+const batchLengthLimit = "max batch length"
+
+// LimitError is returned by the payload parsing, when the payload exceeds the limit.
+type LimitError struct {
+	Limit string // limit name (e.g. "max depth")
+	Max   int    // limit value
+}
+
+// Error implements error interface.
+func (err *LimitError) Error() string {
+	return "jsonrpc: payload exceeds " + err.Limit + " limit (" + strconv.Itoa(err.Max) + ")"
+}
+
+// parseError converts the payload parsing error into the RPC error. Exceeded batch length is reported as an invalid
+// request, and other limits - as a parse error with details.
+func parseError(err error) *rpcErrors.Error {
+	var limitErr *LimitError
+
+	if !errors.As(err, &limitErr) {
+		return rpcErrors.New(rpcErrors.Parse)
+	}
+
+	result := rpcErrors.New(rpcErrors.Parse)
+
+	if limitErr.Limit == batchLengthLimit {
+		result = rpcErrors.New(rpcErrors.InvalidRequest)
+	}
+
+	result.Data = limitErr.Error()
+
+	return result
+}
+
+// check scans the payload and checks the limits. Scanning does not validate JSON syntax (malformed payloads are
+// reported by the decoder).
+func (limits Limits) check(in []byte) error { //nolint:funlen,gocyclo
+	if limits.MaxPayloadBytes > 0 && len(in) > limits.MaxPayloadBytes {
+		return &LimitError{Limit: "max payload bytes", Max: limits.MaxPayloadBytes}
+	}
+
+	if limits.MaxDepth <= 0 && limits.MaxStringLength <= 0 && limits.MaxElements <= 0 && limits.MaxBatchLength <= 0 {
+		return nil
+	}
+
+	type container struct {
+		elements int
+		empty    bool // no elements were found yet
+	}
+
+	var (
+		stack    = make([]container, 0, 8) //nolint:gomnd
+		isBatch  bool
+		inString bool
+		escaped  bool
+		strStart int
+	)
+
+	// element counts the new element of the current container
+	element := func() error {
+		top := &stack[len(stack)-1]
+		top.elements++
+		top.empty = false
+
+		if isBatch && len(stack) == 1 && limits.MaxBatchLength > 0 && top.elements > limits.MaxBatchLength {
+			return &LimitError{Limit: batchLengthLimit, Max: limits.MaxBatchLength}
+		}
+
+		if limits.MaxElements > 0 && top.elements > limits.MaxElements {
+			return &LimitError{Limit: "max elements", Max: limits.MaxElements}
+		}
+
+		return nil
+	}
+
+	for i, c := range in {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+
+				if limits.MaxStringLength > 0 && i-strStart-1 > limits.MaxStringLength {
+					return &LimitError{Limit: "max string length", Max: limits.MaxStringLength}
+				}
+			}
+
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+			continue
+
+		case ',':
+			if len(stack) > 0 {
+				if err := element(); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		// value (or object key) starting - the first element of the container is counted here
+		if len(stack) > 0 && stack[len(stack)-1].empty {
+			if err := element(); err != nil {
+				return err
+			}
+		}
+
+		switch c {
+		case '"':
+			inString, strStart = true, i
+
+		case '[', '{':
+			if len(stack) == 0 && c == '[' {
+				isBatch = true
+			}
+
+			stack = append(stack, container{empty: true})
+
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				return &LimitError{Limit: "max depth", Max: limits.MaxDepth}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package kernel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func TestLimits_check(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		giveLimits Limits
+		giveJSON   string
+		wantLimit  string
+	}{
+		{
+			name:     "without limits",
+			giveJSON: `[[[[{"a": "` + strings.Repeat("x", 1000) + `"}]]]]`,
+		},
+		{
+			name:       "payload size",
+			giveLimits: Limits{MaxPayloadBytes: 10},
+			giveJSON:   `{"jsonrpc": "2.0"}`,
+			wantLimit:  "max payload bytes",
+		},
+		{
+			name:       "depth",
+			giveLimits: Limits{MaxDepth: 3},
+			giveJSON:   `{"params": {"a": [1, {"b": 2}]}}`,
+			wantLimit:  "max depth",
+		},
+		{
+			name:       "depth (allowed)",
+			giveLimits: Limits{MaxDepth: 3},
+			giveJSON:   `{"params": {"a": [1, 2]}, "b": {"c": []}}`,
+		},
+		{
+			name:       "depth (brackets in strings are ignored)",
+			giveLimits: Limits{MaxDepth: 1},
+			giveJSON:   `{"params": "[[{{\"[{"}`,
+		},
+		{
+			name:       "string length",
+			giveLimits: Limits{MaxStringLength: 5},
+			giveJSON:   `{"method": "foobar"}`,
+			wantLimit:  "max string length",
+		},
+		{
+			name:       "string length (keys are checked too)",
+			giveLimits: Limits{MaxStringLength: 5},
+			giveJSON:   `{"foobar": 1}`,
+			wantLimit:  "max string length",
+		},
+		{
+			name:       "string length (allowed, with escaped quote)",
+			giveLimits: Limits{MaxStringLength: 5},
+			giveJSON:   `{"a": "fo\"o", "b": ""}`,
+		},
+		{
+			name:       "array elements",
+			giveLimits: Limits{MaxElements: 3},
+			giveJSON:   `{"params": [1, "2", [3], {}]}`,
+			wantLimit:  "max elements",
+		},
+		{
+			name:       "object elements",
+			giveLimits: Limits{MaxElements: 3},
+			giveJSON:   `{"jsonrpc": "2.0", "method": "foo", "params": {}, "id": 1}`,
+			wantLimit:  "max elements",
+		},
+		{
+			name:       "elements (allowed)",
+			giveLimits: Limits{MaxElements: 3},
+			giveJSON:   `{"method": "foo", "params": [[1, 2, 3], {"a": [], "b": {}}, ["x,y,z,w"]], "id": 1}`,
+		},
+		{
+			name:       "batch length",
+			giveLimits: Limits{MaxBatchLength: 2, MaxElements: 2},
+			giveJSON:   `[{}, {}, {}]`,
+			wantLimit:  "max batch length",
+		},
+		{
+			name:       "batch length (nested arrays are not batches)",
+			giveLimits: Limits{MaxBatchLength: 2},
+			giveJSON:   `{"params": [1, 2, 3]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.giveLimits.check([]byte(tt.giveJSON))
+
+			if tt.wantLimit == "" {
+				assert.NoError(t, err)
+			} else if assert.IsType(t, &LimitError{}, err) {
+				assert.Equal(t, tt.wantLimit, err.(*LimitError).Limit)
+			}
+		})
+	}
+}
+
+func TestKernel_HandleJSONRequestLimits(t *testing.T) {
+	t.Parallel()
+
+	kernel := New(rpcRouter.New())
+	kernel.Limits = Limits{MaxDepth: 2, MaxBatchLength: 1}
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32700, "message": "Parse error",
+			"data": "jsonrpc: payload exceeds max depth limit (2)"}}`,
+		string(kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo", "params": [[1]], "id": 1}`))),
+	)
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request",
+			"data": "jsonrpc: payload exceeds max batch length limit (1)"}}`,
+		string(kernel.HandleJSONRequest([]byte(`[{"jsonrpc": "2.0", "method": "foo", "id": 1}, {}]`))),
+	)
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32601, "message": "Method not found"}, "id": 1}`,
+		string(kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo", "params": [1], "id": 1}`))),
+	)
+}
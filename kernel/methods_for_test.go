@@ -1,6 +1,8 @@
 package kernel
 
 import (
+	"context"
+
 	"github.com/tarampampam/go-jsonrpc"
 	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
 )
@@ -71,3 +73,49 @@ func (*getDataMethod) GetName() string            { return "get_data" }
 func (*getDataMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
 	return getDataMethodResult{"hello", 5}, nil
 }
+
+// blockingMethod blocks until the context is done (or release channel is closed).
+type blockingMethod struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingMethod() *blockingMethod {
+	return &blockingMethod{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (*blockingMethod) GetParamsType() interface{} { return nil }
+func (*blockingMethod) GetName() string            { return "block" }
+func (m *blockingMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return m.HandleContext(context.Background(), nil)
+}
+
+func (m *blockingMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	m.started <- struct{}{}
+
+	select {
+	case <-ctx.Done():
+	case <-m.release:
+	}
+
+	return "released", nil
+}
+
+type fakeSession struct{ id string }
+
+func (s fakeSession) ID() string                     { return s.id }
+func (fakeSession) Notify(string, interface{}) error { return nil }
+func (fakeSession) Done() <-chan struct{}            { return nil }
+
+// stuckMethod ignores the context and blocks until release channel is closed.
+type stuckMethod struct {
+	release chan struct{}
+}
+
+func (*stuckMethod) GetParamsType() interface{} { return nil }
+func (*stuckMethod) GetName() string            { return "stuck" }
+func (m *stuckMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	<-m.release
+
+	return "released", nil
+}
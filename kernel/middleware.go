@@ -0,0 +1,58 @@
+package kernel
+
+import (
+	"context"
+
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+)
+
+type (
+	// RequestHandler processes the request and returns the response. Response is returned for notifications too
+	// (kernel drops it after the middlewares chain), so middlewares can observe the errors.
+	RequestHandler func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response
+
+	// Middleware wraps the RequestHandler and allows to run some code before and after each request processing
+	// (logging, metrics, tracing, etc.).
+	Middleware func(next RequestHandler) RequestHandler
+)
+
+type batch struct {
+	index, size int
+}
+
+type batchCtxKey struct{}
+
+// Use appends middlewares into the kernel middlewares stack. Middlewares are executed in the order of registration,
+// for each request (batch items are processed separately, see BatchFromContext).
+func (kernel *Kernel) Use(middlewares ...Middleware) {
+	kernel.middlewaresMutex.Lock()
+	kernel.middlewares = append(kernel.middlewares, middlewares...)
+	kernel.middlewaresMutex.Unlock()
+}
+
+// requestHandler builds a request handler with all registered middlewares.
+func (kernel *Kernel) requestHandler() RequestHandler {
+	kernel.middlewaresMutex.RLock()
+	var middlewares = kernel.middlewares
+	kernel.middlewaresMutex.RUnlock()
+
+	var handler RequestHandler = kernel.handleRequest
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// BatchFromContext returns the request index in the batch and the batch size (ok is false for non-batch requests).
+func BatchFromContext(ctx context.Context) (index, size int, ok bool) {
+	b, ok := ctx.Value(batchCtxKey{}).(batch)
+
+	return b.index, b.size, ok
+}
+
+func withBatch(ctx context.Context, b batch) context.Context {
+	return context.WithValue(ctx, batchCtxKey{}, b)
+}
@@ -0,0 +1,106 @@
+package kernel
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func TestKernel_Use(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethod(&subtractMethod{}))
+
+	var (
+		kernel = New(router)
+		mutex  sync.Mutex
+		calls  []string
+	)
+
+	for _, name := range []string{"first", "second"} {
+		name := name
+
+		kernel.Use(func(next RequestHandler) RequestHandler {
+			return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+				mutex.Lock()
+				calls = append(calls, name+":"+request.Method)
+				mutex.Unlock()
+
+				return next(ctx, request)
+			}
+		})
+	}
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "result": 1, "id": 1}`,
+		string(kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "subtract", "params": [2, 1], "id": 1}`))),
+	)
+	assert.Equal(t, []string{"first:subtract", "second:subtract"}, calls)
+}
+
+func TestKernel_MiddlewareObservesNotifications(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel    = New(rpcRouter.New())
+		responses = make(chan *rpcResponse.Response, 1)
+	)
+
+	kernel.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+			response := next(ctx, request)
+			responses <- response
+
+			return response
+		}
+	})
+
+	assert.Empty(t, kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo"}`)))
+
+	response := <-responses
+	assert.Equal(t, -32601, response.Error.GetCode())
+	assert.Nil(t, response.ID)
+}
+
+func TestBatchFromContext(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel = New(rpcRouter.New())
+		mutex  sync.Mutex
+		got    [][2]int
+	)
+
+	kernel.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+			index, size, ok := BatchFromContext(ctx)
+
+			mutex.Lock()
+			if ok {
+				got = append(got, [2]int{index, size})
+			} else {
+				got = append(got, [2]int{-1, 0})
+			}
+			mutex.Unlock()
+
+			return next(ctx, request)
+		}
+	})
+
+	kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo", "id": 1}`))
+	assert.Equal(t, [][2]int{{-1, 0}}, got)
+
+	got = nil
+
+	kernel.HandleJSONRequest([]byte(`[{"jsonrpc": "2.0", "method": "foo", "id": 1}, {"jsonrpc": "2.0", "method": "bar"}]`))
+	sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+	assert.Equal(t, [][2]int{{0, 2}, {1, 2}}, got)
+}
@@ -0,0 +1,88 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// canonicalNamer is implemented by the routers, that resolve the method names (aliases, versions, mounted
+// sub-routers), e.g. router.Router.
+type canonicalNamer interface {
+	CanonicalNameContext(ctx context.Context, methodName string) (string, bool)
+}
+
+// timeout returns the method execution timeout (zero means "without timeout"). Method name is resolved into the
+// canonical name (including the version, chosen using the context), so the timeout cannot be bypassed using the method
+// alias or version.
+func (kernel *Kernel) timeout(ctx context.Context, methodName string) time.Duration {
+	if len(kernel.MethodTimeouts) == 0 {
+		return kernel.DefaultTimeout
+	}
+
+	if namer, ok := kernel.router.(canonicalNamer); ok {
+		if name, found := namer.CanonicalNameContext(ctx, methodName); found {
+			methodName = name
+		}
+	}
+
+	if timeout, ok := kernel.MethodTimeouts[methodName]; ok {
+		return timeout
+	}
+
+	// timeout of the method without version is applied to all its versions
+	if i := strings.Index(methodName, rpcRouter.VersionSeparator); i >= 0 {
+		if timeout, ok := kernel.MethodTimeouts[methodName[:i]]; ok {
+			return timeout
+		}
+	}
+
+	return kernel.DefaultTimeout
+}
+
+// execute invokes the method in a separate goroutine, so the response is returned as soon as the context is done
+// (timeout, cancellation or client disconnection), and the slow method does not block the rest of a batch. Method
+// should respect the context, otherwise it continues execution in background.
+func (kernel *Kernel) execute(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+	type outcome struct {
+		result interface{}
+		err    jsonrpc.Error
+	}
+
+	var done = make(chan outcome, 1)
+
+	go func() {
+		result, err := kernel.invoke(ctx, methodName, params)
+
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		if err := contextError(ctx); err != nil { // result is not actual anymore
+			return nil, err
+		}
+
+		return o.result, o.err
+
+	case <-ctx.Done():
+		return nil, contextError(ctx)
+	}
+}
+
+// contextError converts the context error into RPC error (or nil, when the context is not done).
+func contextError(ctx context.Context) jsonrpc.Error {
+	switch err := ctx.Err(); {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return rpcErrors.New(rpcErrors.Timeout)
+	default:
+		return rpcErrors.New(rpcErrors.RequestCancelled)
+	}
+}
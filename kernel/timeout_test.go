@@ -0,0 +1,151 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func TestKernel_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	const timeoutErr = `{"jsonrpc": "2.0", "error": {"code": -32001, "message": "Request timeout"}, "id": 1}`
+
+	cases := []struct {
+		name               string
+		giveDefaultTimeout time.Duration
+		giveMethodTimeouts map[string]time.Duration
+		giveJSON           string
+		wantJSON           string
+	}{
+		{
+			name:               "default timeout",
+			giveDefaultTimeout: time.Millisecond * 10,
+			giveJSON:           `{"jsonrpc": "2.0", "method": "block", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "method timeout",
+			giveMethodTimeouts: map[string]time.Duration{"block": time.Millisecond * 10},
+			giveJSON:           `{"jsonrpc": "2.0", "method": "block", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "method timeout overrides default",
+			giveDefaultTimeout: time.Hour,
+			giveMethodTimeouts: map[string]time.Duration{"stuck": time.Millisecond * 10},
+			giveJSON:           `{"jsonrpc": "2.0", "method": "stuck", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "alias cannot bypass the method timeout",
+			giveDefaultTimeout: time.Hour,
+			giveMethodTimeouts: map[string]time.Duration{"stuck": time.Millisecond * 10},
+			giveJSON:           `{"jsonrpc": "2.0", "method": "stuck.alias", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "version cannot bypass the method timeout",
+			giveDefaultTimeout: time.Hour,
+			giveMethodTimeouts: map[string]time.Duration{"stuck": time.Millisecond * 10},
+			giveJSON:           `{"jsonrpc": "2.0", "method": "stuck@v2", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "method ignores the context",
+			giveDefaultTimeout: time.Millisecond * 10,
+			giveJSON:           `{"jsonrpc": "2.0", "method": "stuck", "id": 1}`,
+			wantJSON:           timeoutErr,
+		},
+		{
+			name:               "batch is not blocked by the slow method",
+			giveDefaultTimeout: time.Millisecond * 10,
+			giveJSON: `[
+				{"jsonrpc": "2.0", "method": "stuck", "id": 1},
+				{"jsonrpc": "2.0", "method": "subtract", "params": [3, 1], "id": 2}
+			]`,
+			wantJSON: `[{"jsonrpc": "2.0", "result": 2, "id": 2}, ` + timeoutErr + `]`, // in order of completion
+		},
+		{
+			name:               "notification",
+			giveDefaultTimeout: time.Millisecond * 10,
+			giveJSON:           `{"jsonrpc": "2.0", "method": "stuck"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				router = rpcRouter.New()
+				stuck  = &stuckMethod{release: make(chan struct{})}
+			)
+
+			defer close(stuck.release)
+
+			assert.NoError(t, router.RegisterMethods(newBlockingMethod(), stuck, &subtractMethod{}))
+			assert.NoError(t, router.RegisterAlias("stuck.alias", "stuck"))
+			assert.NoError(t, router.RegisterVersionedMethod("v2", stuck))
+
+			kernel := New(router)
+			kernel.DefaultTimeout = tt.giveDefaultTimeout
+			kernel.MethodTimeouts = tt.giveMethodTimeouts
+
+			result := kernel.HandleJSONRequest([]byte(tt.giveJSON))
+
+			if tt.wantJSON == "" {
+				assert.Empty(t, result)
+			} else {
+				assert.JSONEq(t, tt.wantJSON, string(result))
+			}
+		})
+	}
+}
+
+func TestKernel_TimeoutsContextVersion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = rpcRouter.New()
+		stuck  = &stuckMethod{release: make(chan struct{})}
+	)
+
+	defer close(stuck.release)
+
+	assert.NoError(t, router.RegisterMethod(&subtractMethod{}))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", stuck))
+
+	kernel := New(router)
+	kernel.DefaultTimeout = time.Hour
+	kernel.MethodTimeouts = map[string]time.Duration{"stuck@v2": time.Millisecond * 10}
+
+	// version, chosen using the context, is resolved for the method timeout
+	result := kernel.HandleJSONRequestContext(
+		rpcRouter.WithVersion(context.Background(), "v2"),
+		[]byte(`{"jsonrpc": "2.0", "method": "stuck", "id": 1}`),
+	)
+
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "error": {"code": -32001, "message": "Request timeout"}, "id": 1}`,
+		string(result),
+	)
+}
+
+func TestContextError(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, contextError(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, int(rpcErrors.RequestCancelled), contextError(ctx).GetCode())
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	assert.Equal(t, int(rpcErrors.Timeout), contextError(ctx).GetCode())
+}
@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"net/http"
+)
+
+// WrapHTTP attaches the HTTP request metadata to the request context, and wraps the response writer, so the response
+// metadata (headers and cookies) is written with the response status code. Response metadata, that is set after the
+// response headers writing (e.g. for the streamed responses), is ignored.
+func WrapHTTP(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request) {
+	md := &Metadata{
+		Transport:  TransportHTTP,
+		RemoteAddr: r.RemoteAddr,
+		Header:     r.Header,
+		TLS:        r.TLS,
+	}
+
+	rw := &responseWriter{ResponseWriter: w, md: md}
+
+	r = r.WithContext(NewContext(r.Context(), md))
+
+	if flusher, ok := w.(http.Flusher); ok {
+		return &flushingResponseWriter{responseWriter: rw, flusher: flusher}, r
+	}
+
+	return rw, r
+}
+
+// responseWriter writes the response metadata before the status code.
+type responseWriter struct {
+	http.ResponseWriter
+
+	md          *Metadata
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		for key, values := range w.md.ResponseHeader() {
+			w.Header()[key] = values
+		}
+
+		for _, cookie := range w.md.Cookies() {
+			http.SetCookie(w.ResponseWriter, cookie)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+// flushingResponseWriter is a responseWriter, that implements http.Flusher.
+type flushingResponseWriter struct {
+	*responseWriter
+
+	flusher http.Flusher
+}
+
+func (w *flushingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.flusher.Flush()
+}
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapHTTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/rpc", nil)
+		rr     = httptest.NewRecorder()
+	)
+
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Foo", "bar")
+
+	w, r := WrapHTTP(rr, req)
+
+	md, ok := FromContext(r.Context())
+	assert.True(t, ok)
+	assert.Equal(t, TransportHTTP, md.Transport)
+	assert.Equal(t, "10.0.0.1:1234", md.RemoteAddr)
+	assert.Equal(t, "bar", md.Get("X-Foo"))
+	assert.Nil(t, md.TLS)
+
+	_, isFlusher := w.(http.Flusher)
+	assert.True(t, isFlusher)
+
+	md.SetHeader("X-Result", "ok")
+	md.SetCookie(&http.Cookie{Name: "foo", Value: "bar"})
+
+	_, _ = w.Write([]byte("body"))
+
+	md.SetHeader("X-Late", "ignored")
+	w.(http.Flusher).Flush()
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "ok", rr.Header().Get("X-Result"))
+	assert.Equal(t, "foo=bar", rr.Header().Get("Set-Cookie"))
+	assert.Empty(t, rr.Header().Get("X-Late"))
+	assert.Equal(t, "body", rr.Body.String())
+}
+
+type plainResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestWrapHTTP_WithoutFlusher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodGet, "http://rpc/rpc", nil)
+		rr     = httptest.NewRecorder()
+	)
+
+	w, r := WrapHTTP(plainResponseWriter{rr}, req)
+
+	_, isFlusher := w.(http.Flusher)
+	assert.False(t, isFlusher)
+
+	md, _ := FromContext(r.Context())
+	md.SetHeader("X-Result", "ok")
+
+	w.WriteHeader(http.StatusAccepted)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "ok", rr.Header().Get("X-Result"))
+}
@@ -0,0 +1,108 @@
+// Package metadata provides transport-agnostic calls metadata (request headers, remote address, TLS state, session
+// ID), that transports attach to the context, and response metadata (headers and cookies), that methods can set.
+package metadata
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync"
+)
+
+// Transport names.
+const (
+	TransportHTTP   = "http"
+	TransportStream = "stream"
+)
+
+// Metadata is a call metadata.
+type Metadata struct {
+	Transport  string               // transport name (e.g. TransportHTTP)
+	RemoteAddr string               // client address (can be empty)
+	Header     http.Header          // incoming request headers (can be nil)
+	TLS        *tls.ConnectionState // TLS connection state (nil for the plain connections)
+	SessionID  string               // client session ID (for the persistent connections)
+
+	mutex          sync.Mutex
+	responseHeader http.Header
+	cookies        []*http.Cookie
+}
+
+type ctxKey struct{}
+
+// NewContext returns a context with attached metadata.
+func NewContext(ctx context.Context, md *Metadata) context.Context {
+	return context.WithValue(ctx, ctxKey{}, md)
+}
+
+// FromContext returns metadata, attached to the context using NewContext.
+func FromContext(ctx context.Context) (*Metadata, bool) {
+	md, ok := ctx.Value(ctxKey{}).(*Metadata)
+
+	return md, ok && md != nil
+}
+
+// Get returns the first incoming header value (case-insensitive).
+func (md *Metadata) Get(key string) string { return md.Header.Get(key) }
+
+// PeerCertificate returns the client TLS certificate subject common name (or empty string).
+func (md *Metadata) PeerCertificate() string {
+	if md.TLS == nil || len(md.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+
+	return md.TLS.PeerCertificates[0].Subject.CommonName
+}
+
+// SetHeader sets the response header (transports without headers support ignore it).
+func (md *Metadata) SetHeader(key, value string) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	if md.responseHeader == nil {
+		md.responseHeader = make(http.Header)
+	}
+
+	md.responseHeader.Set(key, value)
+}
+
+// AddHeader adds the response header value.
+func (md *Metadata) AddHeader(key, value string) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	if md.responseHeader == nil {
+		md.responseHeader = make(http.Header)
+	}
+
+	md.responseHeader.Add(key, value)
+}
+
+// SetCookie adds the response cookie (transports without cookies support ignore it).
+func (md *Metadata) SetCookie(cookie *http.Cookie) {
+	md.mutex.Lock()
+	md.cookies = append(md.cookies, cookie)
+	md.mutex.Unlock()
+}
+
+// ResponseHeader returns a copy of the response headers.
+func (md *Metadata) ResponseHeader() http.Header {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	result := make(http.Header, len(md.responseHeader))
+
+	for key, values := range md.responseHeader {
+		result[key] = append([]string(nil), values...)
+	}
+
+	return result
+}
+
+// Cookies returns the response cookies.
+func (md *Metadata) Cookies() []*http.Cookie {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	return append([]*http.Cookie(nil), md.cookies...)
+}
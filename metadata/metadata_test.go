@@ -0,0 +1,71 @@
+package metadata
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = FromContext(NewContext(context.Background(), nil))
+	assert.False(t, ok)
+
+	md := &Metadata{Transport: TransportHTTP}
+
+	got, ok := FromContext(NewContext(context.Background(), md))
+	assert.True(t, ok)
+	assert.Same(t, md, got)
+}
+
+func TestMetadata_Get(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", (&Metadata{}).Get("X-Foo"))
+	assert.Equal(t, "bar", (&Metadata{Header: http.Header{"X-Foo": {"bar"}}}).Get("x-foo"))
+}
+
+func TestMetadata_PeerCertificate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", (&Metadata{}).PeerCertificate())
+	assert.Equal(t, "", (&Metadata{TLS: &tls.ConnectionState{}}).PeerCertificate())
+
+	md := &Metadata{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+		{Subject: pkix.Name{CommonName: "client"}},
+	}}}
+
+	assert.Equal(t, "client", md.PeerCertificate())
+}
+
+func TestMetadata_ResponseHeader(t *testing.T) {
+	t.Parallel()
+
+	md := &Metadata{}
+
+	assert.Empty(t, md.ResponseHeader())
+	assert.Empty(t, md.Cookies())
+
+	md.SetHeader("X-Foo", "1")
+	md.AddHeader("X-Foo", "2")
+	md.SetHeader("X-Bar", "3")
+	md.SetCookie(&http.Cookie{Name: "foo", Value: "bar"})
+
+	header := md.ResponseHeader()
+	assert.Equal(t, []string{"1", "2"}, header["X-Foo"])
+	assert.Equal(t, "3", header.Get("X-Bar"))
+
+	header.Set("X-Bar", "changed") // copy is returned
+	assert.Equal(t, "3", md.ResponseHeader().Get("X-Bar"))
+
+	assert.Equal(t, []*http.Cookie{{Name: "foo", Value: "bar"}}, md.Cookies())
+}
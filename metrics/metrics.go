@@ -0,0 +1,146 @@
+// Package metrics implements kernel and router instrumentation: calls and errors counters, calls latency, in-flight
+// requests, batch sizes and parse errors. Metrics are passed into the Recorder - Registry is a built-in recorder,
+// that exposes metrics in the Prometheus text format (without external dependencies):
+//
+//	registry := metrics.NewRegistry()
+//	metrics.Instrument(kernel, registry)
+//	http.Handle("/metrics", registry)
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// UnknownMethod is used as a method name for the calls, that were not resolved into the registered method (unknown
+// methods, methods handled by the router fallback, invalid requests), to limit labels cardinality. Registered methods
+// are labeled with their canonical names (aliases and versions are resolved).
+const UnknownMethod = "unknown"
+
+// canonicalNamer is implemented by the routers, that resolve the method names (e.g. router.Router).
+type canonicalNamer interface {
+	CanonicalNameContext(ctx context.Context, methodName string) (string, bool)
+}
+
+// Recorder records metrics (can be implemented for any metrics backend).
+type Recorder interface {
+	// InFlight changes the number of in-flight requests.
+	InFlight(delta int)
+
+	// Call records the method call. Code is zero for successful calls.
+	Call(method string, code rpcErrors.Code, duration time.Duration)
+
+	// Batch records the batch request size.
+	Batch(size int)
+
+	// ParseError records the payload parsing error.
+	ParseError()
+}
+
+// Instrument registers kernel Middleware and PayloadHook.
+func Instrument(kernel *rpcKernel.Kernel, recorder Recorder) {
+	kernel.Use(Middleware(recorder, kernel.Router()))
+	kernel.Hook(PayloadHook(recorder))
+}
+
+// Middleware returns kernel middleware, that records calls metrics. Router (kernel router) is used for the method
+// names resolving.
+func Middleware(recorder Recorder, router jsonrpc.Router) rpcKernel.Middleware {
+	return func(next rpcKernel.RequestHandler) rpcKernel.RequestHandler {
+		return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+			var startedAt = time.Now()
+
+			recorder.InFlight(1)
+			defer recorder.InFlight(-1)
+
+			response := next(ctx, request)
+
+			var code rpcErrors.Code
+
+			if response != nil && response.Error != nil {
+				code = response.Error.Code
+			}
+
+			recorder.Call(routerMethodLabel(ctx, router, request.Method), code, time.Since(startedAt))
+
+			return response
+		}
+	}
+}
+
+// PayloadHook returns kernel payload hook, that records batch sizes and parse errors.
+func PayloadHook(recorder Recorder) rpcKernel.PayloadHook {
+	return func(ctx context.Context, payload rpcKernel.Payload) (context.Context, func()) {
+		switch {
+		case payload.ParseError != nil:
+			recorder.ParseError()
+		case payload.Batch:
+			recorder.Batch(payload.Requests)
+		}
+
+		return ctx, nil
+	}
+}
+
+// RouterMiddleware returns router middleware, that records calls metrics. It can be used, when the router is used
+// without kernel (e.g. by the REST or XML-RPC gateways). Do not use it together with kernel Middleware.
+func RouterMiddleware(recorder Recorder) rpcRouter.Middleware {
+	return func(next rpcRouter.Handler) rpcRouter.Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			var startedAt = time.Now()
+
+			recorder.InFlight(1)
+			defer recorder.InFlight(-1)
+
+			result, err := next(ctx, methodName, params)
+
+			var code rpcErrors.Code
+
+			if err != nil {
+				code = rpcErrors.Code(err.GetCode())
+			}
+
+			label := UnknownMethod
+
+			if name, resolved := rpcRouter.MethodNameFromContext(ctx); resolved {
+				label = name
+			}
+
+			recorder.Call(label, code, time.Since(startedAt))
+
+			return result, err
+		}
+	}
+}
+
+// routerMethodLabel returns the canonical name of the method, registered in the router (or UnknownMethod).
+func routerMethodLabel(ctx context.Context, router jsonrpc.Router, method string) string {
+	switch {
+	case method == rpcKernel.CancelRequestMethod:
+		return method
+
+	case router == nil || method == "":
+		return UnknownMethod
+	}
+
+	if namer, ok := router.(canonicalNamer); ok {
+		if name, found := namer.CanonicalNameContext(ctx, method); found {
+			return name
+		}
+
+		return UnknownMethod
+	}
+
+	if router.MethodIsRegistered(method) {
+		return method
+	}
+
+	return UnknownMethod
+}
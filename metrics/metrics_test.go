@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type call struct {
+	method string
+	code   rpcErrors.Code
+}
+
+type fakeRecorder struct {
+	mutex       sync.Mutex
+	inFlight    []int
+	calls       []call
+	batches     []int
+	parseErrors int
+}
+
+func (r *fakeRecorder) InFlight(delta int) {
+	r.mutex.Lock()
+	r.inFlight = append(r.inFlight, delta)
+	r.mutex.Unlock()
+}
+
+func (r *fakeRecorder) Call(method string, code rpcErrors.Code, _ time.Duration) {
+	r.mutex.Lock()
+	r.calls = append(r.calls, call{method: method, code: code})
+	r.mutex.Unlock()
+}
+
+func (r *fakeRecorder) Batch(size int) { r.batches = append(r.batches, size) }
+func (r *fakeRecorder) ParseError()    { r.parseErrors++ }
+
+type pingMethod struct{}
+
+func (*pingMethod) GetParamsType() interface{}                        { return nil }
+func (*pingMethod) GetName() string                                   { return "ping" }
+func (*pingMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return "pong", nil }
+
+// rejectingMiddleware rejects the calls before the method invoking (like authentication middleware does).
+func rejectingMiddleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		if strings.HasPrefix(methodName, "secret.") {
+			return nil, rpcErrors.New(rpcErrors.Unauthorized)
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+func fallback(_ context.Context, methodName string, _ interface{}) (interface{}, jsonrpc.Error) {
+	if strings.HasPrefix(methodName, "fallback.") {
+		return "fallback", nil
+	}
+
+	return nil, rpcErrors.New(rpcErrors.MethodNotFound)
+}
+
+func TestInstrument(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		giveJSON        string
+		wantCalls       []call
+		wantBatches     []int
+		wantParseErrors int
+	}{
+		{
+			name:      "success",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "ping", "id": 1}`,
+			wantCalls: []call{{method: "ping"}},
+		},
+		{
+			name:      "notification",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "ping"}`,
+			wantCalls: []call{{method: "ping"}},
+		},
+		{
+			name:      "unknown method",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "foo", "id": 1}`,
+			wantCalls: []call{{method: UnknownMethod, code: rpcErrors.MethodNotFound}},
+		},
+		{
+			name:      "alias",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "ping.alias", "id": 1}`,
+			wantCalls: []call{{method: "ping"}},
+		},
+		{
+			name:      "rejected unknown method",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "secret.foo", "id": 1}`,
+			wantCalls: []call{{method: UnknownMethod, code: rpcErrors.Unauthorized}},
+		},
+		{
+			name:      "fallback",
+			giveJSON:  `{"jsonrpc": "2.0", "method": "fallback.foo", "id": 1}`,
+			wantCalls: []call{{method: UnknownMethod}},
+		},
+		{
+			name:        "batch",
+			giveJSON:    `[{"jsonrpc": "2.0", "method": "ping", "id": 1}, {"jsonrpc": "2.0", "method": "ping", "id": 2}]`,
+			wantCalls:   []call{{method: "ping"}, {method: "ping"}},
+			wantBatches: []int{2},
+		},
+		{
+			name:            "parse error",
+			giveJSON:        `{`,
+			wantParseErrors: 1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				router   = rpcRouter.New()
+				recorder = &fakeRecorder{}
+			)
+
+			assert.NoError(t, router.RegisterMethod(&pingMethod{}))
+			assert.NoError(t, router.RegisterAlias("ping.alias", "ping"))
+
+			router.Use(rejectingMiddleware)
+			router.Fallback = fallback
+
+			kernel := rpcKernel.New(router)
+			Instrument(kernel, recorder)
+
+			kernel.HandleJSONRequest([]byte(tt.giveJSON))
+
+			assert.Equal(t, tt.wantCalls, recorder.calls)
+			assert.Equal(t, tt.wantBatches, recorder.batches)
+			assert.Equal(t, tt.wantParseErrors, recorder.parseErrors)
+
+			var inFlight int
+			for _, delta := range recorder.inFlight {
+				inFlight += delta
+			}
+
+			assert.Equal(t, 0, inFlight)
+			assert.Len(t, recorder.inFlight, len(tt.wantCalls)*2)
+		})
+	}
+}
+
+func TestInstrumentContextVersion(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router   = rpcRouter.New()
+		recorder = &fakeRecorder{}
+	)
+
+	assert.NoError(t, router.RegisterMethod(&pingMethod{}))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", &pingMethod{}))
+
+	kernel := rpcKernel.New(router)
+	Instrument(kernel, recorder)
+
+	kernel.HandleJSONRequestContext(
+		rpcRouter.WithVersion(context.Background(), "v2"),
+		[]byte(`{"jsonrpc": "2.0", "method": "ping", "id": 1}`),
+	)
+
+	assert.Equal(t, []call{{method: "ping@v2"}}, recorder.calls)
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router   = rpcRouter.New()
+		recorder = &fakeRecorder{}
+	)
+
+	assert.NoError(t, router.RegisterMethod(&pingMethod{}))
+	assert.NoError(t, router.RegisterAlias("ping.alias", "ping"))
+	router.Use(RouterMiddleware(recorder), rejectingMiddleware)
+
+	for _, name := range []string{"ping", "ping.alias", "foo", "secret.foo"} {
+		_, _ = router.InvokeContext(context.Background(), name, nil)
+	}
+
+	assert.Equal(t, []call{
+		{method: "ping"},
+		{method: "ping"},
+		{method: UnknownMethod, code: rpcErrors.MethodNotFound},
+		{method: UnknownMethod, code: rpcErrors.Unauthorized},
+	}, recorder.calls)
+	assert.Equal(t, []int{1, -1, 1, -1, 1, -1, 1, -1}, recorder.inFlight)
+}
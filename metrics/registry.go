@@ -0,0 +1,312 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+const (
+	// DefaultNamespace is used as metrics names prefix.
+	DefaultNamespace = "jsonrpc"
+
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultDurationBuckets are calls duration histogram buckets (in seconds).
+func DefaultDurationBuckets() []float64 {
+	return []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} //nolint:gomnd
+}
+
+// DefaultBatchSizeBuckets are batch size histogram buckets.
+func DefaultBatchSizeBuckets() []float64 {
+	return []float64{1, 2, 5, 10, 25, 50, 100} //nolint:gomnd
+}
+
+type errorKey struct {
+	method string
+	code   rpcErrors.Code
+}
+
+// Registry is an in-memory Recorder, that exposes metrics in the Prometheus text exposition format (it implements
+// http.Handler interface).
+type Registry struct {
+	mutex       sync.Mutex
+	calls       map[string]uint64
+	errors      map[errorKey]uint64
+	durations   map[string]*histogram
+	batchSizes  *histogram
+	inFlight    int64
+	parseErrors uint64
+	cacheHits   map[string]uint64
+	cacheMisses map[string]uint64
+
+	// Namespace is used as metrics names prefix (DefaultNamespace by default).
+	Namespace string
+
+	// DurationBuckets are calls duration histogram buckets (in seconds). Should be set before the first call.
+	DurationBuckets []float64
+
+	// BatchSizeBuckets are batch size histogram buckets. Should be set before the first batch.
+	BatchSizeBuckets []float64
+}
+
+// NewRegistry creates new metrics registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		calls:            make(map[string]uint64),
+		errors:           make(map[errorKey]uint64),
+		durations:        make(map[string]*histogram),
+		cacheHits:        make(map[string]uint64),
+		cacheMisses:      make(map[string]uint64),
+		Namespace:        DefaultNamespace,
+		DurationBuckets:  DefaultDurationBuckets(),
+		BatchSizeBuckets: DefaultBatchSizeBuckets(),
+	}
+}
+
+// InFlight implements Recorder interface.
+func (r *Registry) InFlight(delta int) {
+	r.mutex.Lock()
+	r.inFlight += int64(delta)
+	r.mutex.Unlock()
+}
+
+// Call implements Recorder interface.
+func (r *Registry) Call(method string, code rpcErrors.Code, duration time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.calls[method]++
+
+	if code != 0 {
+		r.errors[errorKey{method: method, code: code}]++
+	}
+
+	h, ok := r.durations[method]
+	if !ok {
+		h = newHistogram(r.DurationBuckets)
+		r.durations[method] = h
+	}
+
+	h.observe(duration.Seconds())
+}
+
+// Batch implements Recorder interface.
+func (r *Registry) Batch(size int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.batchSizes == nil {
+		r.batchSizes = newHistogram(r.BatchSizeBuckets)
+	}
+
+	r.batchSizes.observe(float64(size))
+}
+
+// ParseError implements Recorder interface.
+func (r *Registry) ParseError() {
+	r.mutex.Lock()
+	r.parseErrors++
+	r.mutex.Unlock()
+}
+
+// CacheHit records the method result cache hit (it implements cache.Recorder interface).
+func (r *Registry) CacheHit(method string) {
+	r.mutex.Lock()
+	r.cacheHits[method]++
+	r.mutex.Unlock()
+}
+
+// CacheMiss records the method result cache miss (it implements cache.Recorder interface).
+func (r *Registry) CacheMiss(method string) {
+	r.mutex.Lock()
+	r.cacheMisses[method]++
+	r.mutex.Unlock()
+}
+
+// ServeHTTP implements http.Handler interface.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	_ = r.Write(w)
+}
+
+// Write writes metrics in the Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var (
+		buf = bufio.NewWriter(w)
+		ns  = r.Namespace
+	)
+
+	header(buf, ns+"_calls_total", "counter", "Total number of the method calls.")
+
+	for _, method := range sortedKeys(r.calls) {
+		sample(buf, ns+"_calls_total", labels("method", method), float64(r.calls[method]))
+	}
+
+	header(buf, ns+"_errors_total", "counter", "Total number of the method calls errors by error code.")
+
+	errorKeys := make([]errorKey, 0, len(r.errors))
+	for key := range r.errors {
+		errorKeys = append(errorKeys, key)
+	}
+
+	sort.Slice(errorKeys, func(i, j int) bool {
+		if errorKeys[i].method != errorKeys[j].method {
+			return errorKeys[i].method < errorKeys[j].method
+		}
+
+		return errorKeys[i].code < errorKeys[j].code
+	})
+
+	for _, key := range errorKeys {
+		sample(buf, ns+"_errors_total",
+			labels("method", key.method, "code", strconv.Itoa(int(key.code))), float64(r.errors[key]),
+		)
+	}
+
+	header(buf, ns+"_call_duration_seconds", "histogram", "Method calls duration in seconds.")
+
+	for _, method := range sortedHistogramKeys(r.durations) {
+		r.durations[method].write(buf, ns+"_call_duration_seconds", []string{"method", method})
+	}
+
+	header(buf, ns+"_requests_in_flight", "gauge", "Number of requests, that are currently processed.")
+	sample(buf, ns+"_requests_in_flight", "", float64(r.inFlight))
+
+	header(buf, ns+"_batch_size", "histogram", "Batch requests size.")
+
+	if r.batchSizes != nil {
+		r.batchSizes.write(buf, ns+"_batch_size", nil)
+	}
+
+	header(buf, ns+"_parse_errors_total", "counter", "Total number of the payload parsing errors.")
+	sample(buf, ns+"_parse_errors_total", "", float64(r.parseErrors))
+
+	header(buf, ns+"_cache_hits_total", "counter", "Total number of the method results cache hits.")
+
+	for _, method := range sortedKeys(r.cacheHits) {
+		sample(buf, ns+"_cache_hits_total", labels("method", method), float64(r.cacheHits[method]))
+	}
+
+	header(buf, ns+"_cache_misses_total", "counter", "Total number of the method results cache misses.")
+
+	for _, method := range sortedKeys(r.cacheMisses) {
+		sample(buf, ns+"_cache_misses_total", labels("method", method), float64(r.cacheMisses[method]))
+	}
+
+	return buf.Flush()
+}
+
+// histogram is a cumulative histogram.
+type histogram struct {
+	buckets []float64
+	counts  []uint64 // counts per bucket (not cumulative)
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &histogram{buckets: sorted, counts: make([]uint64, len(sorted))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.sum += value
+	h.count++
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+
+			return
+		}
+	}
+}
+
+func (h *histogram) write(w io.Writer, name string, labelPairs []string) {
+	var cumulative uint64
+
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+
+		sample(w, name+"_bucket", labels(append(labelPairs, "le", formatFloat(bound))...), float64(cumulative))
+	}
+
+	sample(w, name+"_bucket", labels(append(labelPairs, "le", "+Inf")...), float64(h.count))
+	sample(w, name+"_sum", labels(labelPairs...), h.sum)
+	sample(w, name+"_count", labels(labelPairs...), float64(h.count))
+}
+
+func header(w io.Writer, name, typ, help string) {
+	_, _ = io.WriteString(w, "# HELP "+name+" "+help+"\n# TYPE "+name+" "+typ+"\n")
+}
+
+func sample(w io.Writer, name, labels string, value float64) {
+	_, _ = io.WriteString(w, name+labels+" "+formatFloat(value)+"\n")
+}
+
+// labels formats label pairs (name, value, name, value, ...).
+func labels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('{')
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(pairs[i] + `="` + escapeLabel(pairs[i+1]) + `"`)
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`) //nolint:gochecknoglobals
+
+func escapeLabel(value string) string { return labelEscaper.Replace(value) }
+
+func formatFloat(value float64) string { return strconv.FormatFloat(value, 'g', -1, 64) }
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedHistogramKeys(m map[string]*histogram) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
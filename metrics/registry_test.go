@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Namespace = "rpc"
+	r.DurationBuckets = []float64{1, 0.1}
+	r.BatchSizeBuckets = []float64{2, 10}
+
+	r.InFlight(1)
+	r.Call("user.get", 0, time.Millisecond*50)
+	r.Call("user.get", -32602, time.Millisecond*500)
+	r.Call(`a"b`, -32603, time.Second*2)
+	r.Batch(3)
+	r.ParseError()
+	r.CacheHit("user.get")
+	r.CacheHit("user.get")
+	r.CacheMiss("user.get")
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, r.Write(&buf))
+	assert.Equal(t, `# HELP rpc_calls_total Total number of the method calls.
+# TYPE rpc_calls_total counter
+rpc_calls_total{method="a\"b"} 1
+rpc_calls_total{method="user.get"} 2
+# HELP rpc_errors_total Total number of the method calls errors by error code.
+# TYPE rpc_errors_total counter
+rpc_errors_total{method="a\"b",code="-32603"} 1
+rpc_errors_total{method="user.get",code="-32602"} 1
+# HELP rpc_call_duration_seconds Method calls duration in seconds.
+# TYPE rpc_call_duration_seconds histogram
+rpc_call_duration_seconds_bucket{method="a\"b",le="0.1"} 0
+rpc_call_duration_seconds_bucket{method="a\"b",le="1"} 0
+rpc_call_duration_seconds_bucket{method="a\"b",le="+Inf"} 1
+rpc_call_duration_seconds_sum{method="a\"b"} 2
+rpc_call_duration_seconds_count{method="a\"b"} 1
+rpc_call_duration_seconds_bucket{method="user.get",le="0.1"} 1
+rpc_call_duration_seconds_bucket{method="user.get",le="1"} 2
+rpc_call_duration_seconds_bucket{method="user.get",le="+Inf"} 2
+rpc_call_duration_seconds_sum{method="user.get"} 0.55
+rpc_call_duration_seconds_count{method="user.get"} 2
+# HELP rpc_requests_in_flight Number of requests, that are currently processed.
+# TYPE rpc_requests_in_flight gauge
+rpc_requests_in_flight 1
+# HELP rpc_batch_size Batch requests size.
+# TYPE rpc_batch_size histogram
+rpc_batch_size_bucket{le="2"} 0
+rpc_batch_size_bucket{le="10"} 1
+rpc_batch_size_bucket{le="+Inf"} 1
+rpc_batch_size_sum 3
+rpc_batch_size_count 1
+# HELP rpc_parse_errors_total Total number of the payload parsing errors.
+# TYPE rpc_parse_errors_total counter
+rpc_parse_errors_total 1
+# HELP rpc_cache_hits_total Total number of the method results cache hits.
+# TYPE rpc_cache_hits_total counter
+rpc_cache_hits_total{method="user.get"} 2
+# HELP rpc_cache_misses_total Total number of the method results cache misses.
+# TYPE rpc_cache_misses_total counter
+rpc_cache_misses_total{method="user.get"} 1
+`, buf.String())
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodGet, "http://test/metrics", nil)
+		rr     = httptest.NewRecorder()
+	)
+
+	NewRegistry().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "jsonrpc_requests_in_flight 0\n")
+}
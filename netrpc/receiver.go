@@ -0,0 +1,176 @@
+package netrpc
+
+import (
+	"errors"
+	"go/token"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// typeOfError is used for methods signature checking.
+var typeOfError = reflect.TypeOf((*error)(nil)).Elem() //nolint:gochecknoglobals
+
+// receiverMethod wraps `net/rpc` receiver method (`func (t *T) MethodName(args T1, reply *T2) error`) into the
+// jsonrpc.Method.
+type receiverMethod struct {
+	name      string
+	receiver  reflect.Value
+	method    reflect.Method
+	argType   reflect.Type
+	replyType reflect.Type
+	json      jsoniter.API
+}
+
+// Methods converts all suitable (by the `net/rpc` rules) receiver methods into the jsonrpc.Method slice. Methods
+// names are `name.MethodName` (when name is empty - receiver type name is used, as `rpc.Register` does).
+func Methods(name string, receiver interface{}) ([]jsonrpc.Method, error) {
+	var (
+		value = reflect.ValueOf(receiver)
+		typ   = reflect.TypeOf(receiver)
+	)
+
+	if receiver == nil {
+		return nil, errors.New("netrpc: receiver should not be nil")
+	}
+
+	if name == "" {
+		name = reflect.Indirect(value).Type().Name()
+	}
+
+	if !token.IsExported(name) {
+		return nil, errors.New("netrpc: receiver name " + name + " is not exported")
+	}
+
+	methods := make([]jsonrpc.Method, 0, typ.NumMethod())
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		if method, ok := newReceiverMethod(name, value, typ.Method(i)); ok {
+			methods = append(methods, method)
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("netrpc: type " + name + " has no suitable methods")
+	}
+
+	return methods, nil
+}
+
+// Register registers all suitable receiver methods in the router. When router supports bulk registration
+// (`RegisterMethods(...jsonrpc.Method) error`), methods are registered atomically.
+func Register(router jsonrpc.Router, name string, receiver interface{}) error {
+	methods, err := Methods(name, receiver)
+	if err != nil {
+		return err
+	}
+
+	if bulk, ok := router.(interface {
+		RegisterMethods(methods ...jsonrpc.Method) error
+	}); ok {
+		return bulk.RegisterMethods(methods...)
+	}
+
+	for _, method := range methods {
+		if err := router.RegisterMethod(method); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newReceiverMethod(name string, receiver reflect.Value, method reflect.Method) (*receiverMethod, bool) {
+	var mType = method.Type
+
+	// method needs three ins (receiver, *args, *reply) and one out (error)
+	if method.PkgPath != "" || mType.NumIn() != 3 || mType.NumOut() != 1 || mType.Out(0) != typeOfError {
+		return nil, false
+	}
+
+	argType, replyType := mType.In(1), mType.In(2) //nolint:gomnd
+
+	if !isExportedOrBuiltinType(argType) || !isExportedOrBuiltinType(replyType) || replyType.Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	return &receiverMethod{
+		name:      name + "." + method.Name,
+		receiver:  receiver,
+		method:    method,
+		argType:   argType,
+		replyType: replyType,
+		json:      jsoniter.ConfigFastest,
+	}, true
+}
+
+func isExportedOrBuiltinType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return token.IsExported(t.Name()) || t.PkgPath() == ""
+}
+
+// GetName returns method name in string representation.
+func (m *receiverMethod) GetName() string { return m.name }
+
+// GetParamsType returns "raw" params holder, because params binding is made by the method itself (positional params
+// with single element are unwrapped, as `net/rpc/jsonrpc` does).
+func (m *receiverMethod) GetParamsType() interface{} { return new(interface{}) }
+
+// Handle calls receiver method.
+func (m *receiverMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	if raw, ok := params.(*interface{}); ok {
+		params = *raw
+	}
+
+	if positional, ok := params.([]interface{}); ok && len(positional) == 1 {
+		params = positional[0]
+	}
+
+	var argv reflect.Value
+
+	if m.argType.Kind() == reflect.Ptr {
+		argv = reflect.New(m.argType.Elem())
+	} else {
+		argv = reflect.New(m.argType)
+	}
+
+	if params != nil {
+		bytes, _ := m.json.Marshal(params)
+		if err := m.json.Unmarshal(bytes, argv.Interface()); err != nil {
+			return nil, rpcErrors.New(rpcErrors.InvalidParams)
+		}
+	}
+
+	if v, ok := argv.Interface().(jsonrpc.Validator); ok {
+		if validationErr := v.Validate(); validationErr != nil {
+			err := rpcErrors.New(rpcErrors.InvalidParams)
+			err.Data = validationErr.Error()
+
+			return nil, err
+		}
+	}
+
+	if m.argType.Kind() != reflect.Ptr {
+		argv = argv.Elem()
+	}
+
+	replyv := reflect.New(m.replyType.Elem())
+
+	if errValue := m.method.Func.Call([]reflect.Value{m.receiver, argv, replyv})[0]; !errValue.IsNil() {
+		if rpcErr, ok := errValue.Interface().(jsonrpc.Error); ok {
+			return nil, rpcErr
+		}
+
+		err := rpcErrors.New(rpcErrors.Internal)
+		err.Data = errValue.Interface().(error).Error()
+
+		return nil, err
+	}
+
+	return replyv.Elem().Interface(), nil
+}
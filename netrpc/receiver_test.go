@@ -0,0 +1,189 @@
+package netrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type (
+	Arith     int
+	ArithArgs struct {
+		A, B int
+	}
+	Quotient struct {
+		Quo, Rem int
+	}
+)
+
+func (a *ArithArgs) Validate() error {
+	if a.A < 0 {
+		return errors.New("negative value")
+	}
+
+	return nil
+}
+
+func (*Arith) Multiply(args *ArithArgs, reply *int) error {
+	*reply = args.A * args.B
+
+	return nil
+}
+
+func (*Arith) Divide(args ArithArgs, quo *Quotient) error {
+	if args.B == 0 {
+		return errors.New("divide by zero")
+	}
+
+	quo.Quo, quo.Rem = args.A/args.B, args.A%args.B
+
+	return nil
+}
+
+func (*Arith) Fail(_ *ArithArgs, _ *int) error {
+	return &rpcErrors.Error{Code: 1, Message: "foo"}
+}
+
+// methods below are not suitable for the `net/rpc`
+func (*Arith) NoReply(_ *ArithArgs) error                     { return nil }
+func (*Arith) NotPointerReply(_ *ArithArgs, _ int) error      { return nil }
+func (*Arith) WrongResult(_ *ArithArgs, _ *int) int           { return 0 }
+func (*Arith) unexported(_ *ArithArgs, _ *int) error          { return nil } //nolint:unused
+func (*Arith) UnexportedArgs(_ *unexportedArgs, _ *int) error { return nil }
+
+type unexportedArgs struct{}
+
+type nothing struct{}
+
+func (nothing) Foo() {}
+
+func TestMethods(t *testing.T) {
+	t.Parallel()
+
+	methods, err := Methods("", new(Arith))
+	assert.NoError(t, err)
+
+	names := make([]string, 0)
+	for _, m := range methods {
+		names = append(names, m.GetName())
+		assert.IsType(t, new(interface{}), m.GetParamsType())
+	}
+
+	assert.ElementsMatch(t, []string{"Arith.Multiply", "Arith.Divide", "Arith.Fail"}, names)
+
+	methods, err = Methods("Calc", new(Arith))
+	assert.NoError(t, err)
+	assert.Equal(t, "Calc.", methods[0].GetName()[:5])
+
+	_, err = Methods("calc", new(Arith))
+	assert.Contains(t, err.Error(), "not exported")
+
+	_, err = Methods("Nothing", nothing{})
+	assert.Contains(t, err.Error(), "no suitable methods")
+
+	_, err = Methods("Foo", nil)
+	assert.Contains(t, err.Error(), "nil")
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+
+	assert.NoError(t, Register(router, "", new(Arith)))
+
+	cases := []struct {
+		name       string
+		giveMethod string
+		giveParams interface{}
+		wantResult interface{}
+		wantErr    *rpcErrors.Error
+	}{
+		{
+			name:       "params as an object",
+			giveMethod: "Arith.Multiply",
+			giveParams: map[string]interface{}{"A": 2, "B": 3},
+			wantResult: 6,
+		},
+		{
+			name:       "positional params with single element",
+			giveMethod: "Arith.Multiply",
+			giveParams: []interface{}{map[string]interface{}{"A": 3, "B": 3}},
+			wantResult: 9,
+		},
+		{
+			name:       "without params",
+			giveMethod: "Arith.Multiply",
+			wantResult: 0,
+		},
+		{
+			name:       "not pointer args",
+			giveMethod: "Arith.Divide",
+			giveParams: map[string]interface{}{"A": 7, "B": 2},
+			wantResult: Quotient{Quo: 3, Rem: 1},
+		},
+		{
+			name:       "wrong params",
+			giveMethod: "Arith.Multiply",
+			giveParams: map[string]interface{}{"A": "foo"},
+			wantErr:    rpcErrors.New(rpcErrors.InvalidParams),
+		},
+		{
+			name:       "params validation",
+			giveMethod: "Arith.Multiply",
+			giveParams: map[string]interface{}{"A": -1},
+			wantErr:    &rpcErrors.Error{Code: rpcErrors.InvalidParams, Message: "Invalid params", Data: "negative value"},
+		},
+		{
+			name:       "method error",
+			giveMethod: "Arith.Divide",
+			giveParams: map[string]interface{}{"A": 1},
+			wantErr:    &rpcErrors.Error{Code: rpcErrors.Internal, Message: "Internal error", Data: "divide by zero"},
+		},
+		{
+			name:       "method RPC error",
+			giveMethod: "Arith.Fail",
+			wantErr:    &rpcErrors.Error{Code: 1, Message: "foo"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := router.Invoke(tt.giveMethod, tt.giveParams)
+
+			if tt.wantErr != nil {
+				assert.Nil(t, res)
+				assert.Equal(t, jsonrpc.Error(tt.wantErr), err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.wantResult, res)
+			}
+		})
+	}
+}
+
+type onlyRegisterRouter struct {
+	jsonrpc.Router
+	names []string
+}
+
+func (r *onlyRegisterRouter) RegisterMethod(m jsonrpc.Method) error {
+	r.names = append(r.names, m.GetName())
+
+	return nil
+}
+
+func TestRegisterWithoutBulkRegistration(t *testing.T) {
+	t.Parallel()
+
+	router := &onlyRegisterRouter{}
+
+	assert.NoError(t, Register(router, "Calc", new(Arith)))
+	assert.Len(t, router.names, 3)
+
+	assert.Error(t, Register(router, "", nothing{}))
+}
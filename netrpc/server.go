@@ -0,0 +1,99 @@
+// Package netrpc allows to use the Router with the standard `net/rpc` package (and vice versa).
+package netrpc
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+	stdJSONRPC "net/rpc/jsonrpc"
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc"
+)
+
+// Server serves `net/rpc` clients using the Router for methods invoking.
+type Server struct {
+	router jsonrpc.Router
+
+	// MethodNameMapper (optional) converts `net/rpc` method name (`Service.Method`) into the router method name.
+	MethodNameMapper func(serviceMethod string) string
+}
+
+// invalidRequest is used as a response body on errors (as `net/rpc` server does).
+type invalidRequest struct{}
+
+// NewServer creates new `net/rpc` server for the router.
+func NewServer(router jsonrpc.Router) *Server {
+	return &Server{router: router}
+}
+
+// ServeConn runs the server on a single connection using `net/rpc/jsonrpc` codec. ServeConn blocks, serving the
+// connection until the client hangs up.
+func (server *Server) ServeConn(conn io.ReadWriteCloser) {
+	server.ServeCodec(stdJSONRPC.NewServerCodec(conn))
+}
+
+// ServeCodec is like ServeConn but uses the specified codec to decode requests and encode responses. Please note -
+// gob codec requires methods results types registration (using `gob.Register`).
+func (server *Server) ServeCodec(codec rpc.ServerCodec) {
+	var (
+		wg      sync.WaitGroup
+		sending sync.Mutex
+	)
+
+	for {
+		var header rpc.Request
+
+		if err := codec.ReadRequestHeader(&header); err != nil {
+			break // the client hangs up (or request cannot be read at all)
+		}
+
+		var params interface{}
+
+		if err := codec.ReadRequestBody(&params); err != nil {
+			server.send(codec, &sending, &header, nil, err.Error())
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(header rpc.Request, params interface{}) {
+			defer wg.Done()
+
+			if result, err := server.invoke(header.ServiceMethod, params); err != nil {
+				server.send(codec, &sending, &header, nil, err.Error())
+			} else {
+				server.send(codec, &sending, &header, result, "")
+			}
+		}(header, params)
+	}
+
+	wg.Wait()
+
+	_ = codec.Close()
+}
+
+func (server *Server) invoke(serviceMethod string, params interface{}) (interface{}, jsonrpc.Error) {
+	var methodName = serviceMethod
+
+	if server.MethodNameMapper != nil {
+		methodName = server.MethodNameMapper(serviceMethod)
+	}
+
+	if router, ok := server.router.(jsonrpc.ContextRouter); ok {
+		return router.InvokeContext(context.Background(), methodName, params)
+	}
+
+	return server.router.Invoke(methodName, params)
+}
+
+func (server *Server) send(codec rpc.ServerCodec, mu *sync.Mutex, req *rpc.Request, body interface{}, err string) {
+	if err != "" {
+		body = invalidRequest{}
+	}
+
+	mu.Lock()
+	_ = codec.WriteResponse(&rpc.Response{ServiceMethod: req.ServiceMethod, Seq: req.Seq, Error: err}, body)
+	mu.Unlock()
+}
@@ -0,0 +1,84 @@
+package netrpc
+
+import (
+	"net"
+	"net/rpc"
+	stdJSONRPC "net/rpc/jsonrpc"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+func newClient(server *Server) *rpc.Client {
+	clientConn, serverConn := net.Pipe()
+
+	go server.ServeConn(serverConn)
+
+	return rpc.NewClientWithCodec(stdJSONRPC.NewClientCodec(clientConn))
+}
+
+func TestServer_ServeConn(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+	assert.NoError(t, Register(router, "", new(Arith)))
+
+	client := newClient(NewServer(router))
+	defer client.Close()
+
+	var product int
+
+	assert.NoError(t, client.Call("Arith.Multiply", ArithArgs{A: 6, B: 7}, &product))
+	assert.Equal(t, 42, product)
+
+	var quo Quotient
+
+	assert.NoError(t, client.Call("Arith.Divide", ArithArgs{A: 7, B: 2}, &quo))
+	assert.Equal(t, Quotient{Quo: 3, Rem: 1}, quo)
+
+	err := client.Call("Arith.Divide", ArithArgs{A: 7}, &quo)
+	assert.IsType(t, rpc.ServerError(""), err)
+	assert.Contains(t, err.Error(), "divide by zero")
+
+	err = client.Call("Arith.Unknown", ArithArgs{}, &quo)
+	assert.Contains(t, err.Error(), "Method not found")
+
+	// concurrent calls
+	calls := make([]*rpc.Call, 0)
+
+	for i := 0; i < 10; i++ {
+		calls = append(calls, client.Go("Arith.Multiply", ArithArgs{A: i, B: i}, new(int), nil))
+	}
+
+	for i, call := range calls {
+		<-call.Done
+		assert.NoError(t, call.Error)
+		assert.Equal(t, i*i, *call.Reply.(*int))
+	}
+}
+
+type plainRouter struct {
+	jsonrpc.Router
+}
+
+func (plainRouter) Invoke(methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+	return []interface{}{methodName, params}, nil
+}
+
+func TestServer_MethodNameMapper(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(plainRouter{})
+	server.MethodNameMapper = strings.ToLower
+
+	client := newClient(server)
+	defer client.Close()
+
+	var reply []interface{}
+
+	assert.NoError(t, client.Call("Service.Method", []int{1}, &reply))
+	assert.Equal(t, []interface{}{"service.method", []interface{}{float64(1)}}, reply)
+}
@@ -0,0 +1,146 @@
+// Package progress implements progress reporting from the long-running methods, following the LSP `$/progress`
+// pattern: client passes a progress token in the request params (`workDoneToken` and/or `partialResultToken`), and
+// the method sends `$/progress` notifications with the token into the client session (persistent connection or SSE).
+package progress
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+const (
+	// Method is the progress notifications method name.
+	Method = "$/progress"
+
+	// WorkDoneTokenField is the request params field with the work done progress token.
+	WorkDoneTokenField = "workDoneToken"
+
+	// PartialResultTokenField is the request params field with the partial results token.
+	PartialResultTokenField = "partialResultToken"
+)
+
+// Progress value kinds.
+const (
+	KindBegin  = "begin"
+	KindReport = "report"
+	KindEnd    = "end"
+)
+
+type (
+	// Params is the progress notification params.
+	Params struct {
+		Token interface{} `json:"token"`
+		Value interface{} `json:"value"`
+	}
+
+	// Value is the work done progress value.
+	Value struct {
+		Kind       string `json:"kind"`
+		Title      string `json:"title,omitempty"`
+		Message    string `json:"message,omitempty"`
+		Percentage *int   `json:"percentage,omitempty"`
+	}
+)
+
+// Reporter sends progress notifications. Nil Reporter is valid and does nothing, so methods can use it without
+// checks (e.g. when the client did not pass a token).
+type Reporter struct {
+	session            session.Session
+	workDoneToken      interface{}
+	partialResultToken interface{}
+
+	mutex sync.Mutex
+	ended bool
+}
+
+type ctxKey struct{}
+
+// Middleware is a router middleware, that attaches Reporter to the context, when request params contain a progress
+// token, and the client session is available.
+func Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		if s, ok := session.FromContext(ctx); ok {
+			if p, ok := params.(map[string]interface{}); ok {
+				var reporter = Reporter{
+					session:            s,
+					workDoneToken:      p[WorkDoneTokenField],
+					partialResultToken: p[PartialResultTokenField],
+				}
+
+				if reporter.workDoneToken != nil || reporter.partialResultToken != nil {
+					ctx = context.WithValue(ctx, ctxKey{}, &reporter)
+				}
+			}
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+// FromContext returns Reporter, attached to the context by the Middleware (or nil).
+func FromContext(ctx context.Context) *Reporter {
+	reporter, _ := ctx.Value(ctxKey{}).(*Reporter)
+
+	return reporter
+}
+
+// Begin starts the work done progress. Negative percentage is omitted (means "infinite progress").
+func (r *Reporter) Begin(title, message string, percentage int) error {
+	return r.workDone(Value{Kind: KindBegin, Title: title, Message: message, Percentage: optional(percentage)})
+}
+
+// Report reports the work done progress. Negative percentage is omitted.
+func (r *Reporter) Report(message string, percentage int) error {
+	return r.workDone(Value{Kind: KindReport, Message: message, Percentage: optional(percentage)})
+}
+
+// End ends the work done progress. Reports after the end are ignored.
+func (r *Reporter) End(message string) error {
+	if r == nil {
+		return nil
+	}
+
+	err := r.workDone(Value{Kind: KindEnd, Message: message})
+
+	r.mutex.Lock()
+	r.ended = true
+	r.mutex.Unlock()
+
+	return err
+}
+
+// Partial sends the partial result (when the client passed a partial result token).
+func (r *Reporter) Partial(result interface{}) error {
+	if r == nil || r.partialResultToken == nil {
+		return nil
+	}
+
+	return r.session.Notify(Method, Params{Token: r.partialResultToken, Value: result})
+}
+
+func (r *Reporter) workDone(value Value) error {
+	if r == nil || r.workDoneToken == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ended {
+		return nil
+	}
+
+	return r.session.Notify(Method, Params{Token: r.workDoneToken, Value: value})
+}
+
+func optional(percentage int) *int {
+	if percentage < 0 {
+		return nil
+	}
+
+	return &percentage
+}
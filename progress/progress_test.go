@@ -0,0 +1,153 @@
+package progress
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/httphandler"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+type fakeSession struct {
+	mutex  sync.Mutex
+	params []interface{}
+}
+
+func (*fakeSession) ID() string            { return "1" }
+func (*fakeSession) Done() <-chan struct{} { return nil }
+func (s *fakeSession) Notify(method string, params interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if method == Method {
+		s.params = append(s.params, params)
+	}
+
+	return nil
+}
+
+type reportMethod struct{}
+
+func (*reportMethod) GetParamsType() interface{} { return nil }
+func (*reportMethod) GetName() string            { return "report" }
+func (*reportMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+func (*reportMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	reporter := FromContext(ctx)
+
+	_ = reporter.Begin("Report", "", 0)
+	_ = reporter.Partial([]int{1})
+	_ = reporter.Report("half", 50)
+	_ = reporter.End("done")
+	_ = reporter.Report("ignored", -1)
+
+	return "ok", nil
+}
+
+func newTestRouter(t *testing.T) *rpcRouter.Router {
+	router := rpcRouter.New()
+	router.Use(Middleware)
+
+	assert.NoError(t, router.RegisterMethod(&reportMethod{}))
+
+	return router
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		giveParams interface{}
+		wantParams []interface{}
+	}{
+		{
+			name:       "work done token",
+			giveParams: map[string]interface{}{"workDoneToken": "foo"},
+			wantParams: []interface{}{
+				Params{Token: "foo", Value: Value{Kind: KindBegin, Title: "Report", Percentage: intPtr(0)}},
+				Params{Token: "foo", Value: Value{Kind: KindReport, Message: "half", Percentage: intPtr(50)}},
+				Params{Token: "foo", Value: Value{Kind: KindEnd, Message: "done"}},
+			},
+		},
+		{
+			name:       "partial result token",
+			giveParams: map[string]interface{}{"partialResultToken": 1},
+			wantParams: []interface{}{Params{Token: 1, Value: []int{1}}},
+		},
+		{
+			name:       "without tokens",
+			giveParams: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			name:       "params array",
+			giveParams: []interface{}{"foo"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeSession{}
+
+			res, err := newTestRouter(t).InvokeContext(session.WithSession(context.Background(), s), "report", tt.giveParams)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "ok", res)
+			assert.Equal(t, tt.wantParams, s.params)
+		})
+	}
+}
+
+func TestReporterWithoutSession(t *testing.T) {
+	t.Parallel()
+
+	res, err := newTestRouter(t).InvokeContext(context.Background(), "report", map[string]interface{}{"workDoneToken": 1})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res)
+}
+
+func TestReporterOverSSE(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rpc/", strings.NewReader(
+			`{"jsonrpc": "2.0", "method": "report", "params": {"workDoneToken": "t"}, "id": 1}`,
+		))
+		rr = httptest.NewRecorder()
+	)
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	httphandler.New(rpcKernel.New(newTestRouter(t))).ServeHTTP(rr, req)
+
+	const notification = `data: {"jsonrpc":"2.0","method":"$/progress","params":{"token":"t","value":`
+
+	assert.Equal(t, strings.Join([]string{
+		`event: notification`,
+		notification + `{"kind":"begin","title":"Report","percentage":0}}}`,
+		``,
+		`event: notification`,
+		notification + `{"kind":"report","message":"half","percentage":50}}}`,
+		``,
+		`event: notification`,
+		notification + `{"kind":"end","message":"done"}}}`,
+		``,
+		`event: response`,
+		`data: {"jsonrpc":"2.0","result":"ok","id":1}`,
+		``,
+		``,
+	}, "\n"), rr.Body.String())
+}
@@ -0,0 +1,171 @@
+// Package ratelimit provides token bucket rate limiting of the method calls, keyed by the client (principal, IP
+// address or custom key), configurable globally and per method:
+//
+//	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.ByPrincipal)
+//	limiter.Global = ratelimit.Limit{Count: 100, Period: time.Second}
+//	limiter.SetMethodLimit("report.generate", ratelimit.Limit{Count: 10, Period: time.Minute})
+//	router.Use(authentication.Middleware, limiter.Middleware)
+//
+// Limiter is a router middleware, so each batch entry is counted individually.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+// Limit allows Count calls per Period with bursts up to Burst calls. Zero limit means "without limit".
+type Limit struct {
+	Count  int
+	Period time.Duration
+	Burst  int // bucket capacity (Count by default)
+}
+
+// IsZero checks if the limit is not set.
+func (limit Limit) IsZero() bool { return limit.Count <= 0 || limit.Period <= 0 }
+
+// rate returns tokens generation rate (per second).
+func (limit Limit) rate() float64 { return float64(limit.Count) / limit.Period.Seconds() }
+
+func (limit Limit) burst() int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+
+	return limit.Count
+}
+
+// KeyFunc returns the client key for the call.
+type KeyFunc func(ctx context.Context, method string) string
+
+// ByIP uses the client IP address (from the call metadata) as a key. Proxy headers (e.g. `X-Forwarded-For`) are not
+// trusted, use custom KeyFunc for them.
+func ByIP(ctx context.Context, _ string) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if host, _, err := net.SplitHostPort(md.RemoteAddr); err == nil {
+		return host
+	}
+
+	return md.RemoteAddr
+}
+
+// ByPrincipal uses the authenticated principal ID as a key (see package auth). Unauthenticated calls are keyed by the
+// client IP address.
+func ByPrincipal(ctx context.Context, method string) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return "principal:" + principal.ID
+	}
+
+	return "ip:" + ByIP(ctx, method)
+}
+
+// Limiter limits calls rate. Call must satisfy both the global and the method limits.
+type Limiter struct {
+	store Store
+	key   KeyFunc
+
+	mutex   sync.RWMutex
+	methods map[string]Limit
+
+	// Global is the limit for all calls of the client.
+	Global Limit
+}
+
+// New creates new rate limiter.
+func New(store Store, key KeyFunc) *Limiter {
+	return &Limiter{store: store, key: key, methods: make(map[string]Limit)}
+}
+
+// SetMethodLimit sets (or removes, when zero limit is passed) the method limit. Method is the canonical method name
+// without version (the limit is applied to the method aliases and all its versions).
+func (l *Limiter) SetMethodLimit(method string, limit Limit) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit.IsZero() {
+		delete(l.methods, method)
+	} else {
+		l.methods[method] = limit
+	}
+}
+
+// Middleware is a router middleware, that rejects calls above the limits with the errors.RateLimited error. Error
+// data contains the seconds to wait before retrying (`{"retry_after": 2}`), and `Retry-After` response header is set
+// (for the transports with headers support).
+func (l *Limiter) Middleware(next rpcRouter.Handler) rpcRouter.Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		allowed, retryAfter, err := l.Allow(ctx, methodName)
+		if err != nil {
+			rpcErr := rpcErrors.New(rpcErrors.Internal)
+			rpcErr.Data = err.Error()
+
+			return nil, rpcErr
+		}
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+
+			if md, ok := metadata.FromContext(ctx); ok {
+				md.SetHeader("Retry-After", strconv.Itoa(seconds))
+			}
+
+			rpcErr := rpcErrors.New(rpcErrors.RateLimited)
+			rpcErr.Data = map[string]int{"retry_after": seconds}
+
+			return nil, rpcErr
+		}
+
+		return next(ctx, methodName, params)
+	}
+}
+
+// Allow takes tokens from the method and global buckets of the client (tokens are not taken, when any bucket is
+// empty). Method limit is looked up by the canonical method name without version (see router.MethodNameFromContext),
+// so it cannot be bypassed using the method alias or version.
+func (l *Limiter) Allow(ctx context.Context, methodName string) (allowed bool, retryAfter time.Duration, err error) {
+	if name, resolved := rpcRouter.MethodNameFromContext(ctx); resolved {
+		methodName = name
+	}
+
+	if i := strings.Index(methodName, rpcRouter.VersionSeparator); i >= 0 {
+		methodName = methodName[:i]
+	}
+
+	var (
+		key     = l.key(ctx, methodName)
+		buckets = make([]Bucket, 0, 2) //nolint:gomnd
+	)
+
+	l.mutex.RLock()
+	limit, found := l.methods[methodName]
+	l.mutex.RUnlock()
+
+	if found {
+		buckets = append(buckets, Bucket{Key: "method:" + methodName + "\x00" + key, Limit: limit})
+	}
+
+	if !l.Global.IsZero() {
+		buckets = append(buckets, Bucket{Key: "global\x00" + key, Limit: l.Global})
+	}
+
+	if len(buckets) == 0 {
+		return true, 0, nil
+	}
+
+	return l.store.Take(buckets...)
+}
@@ -0,0 +1,209 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/auth"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type okMethod struct{ name string }
+
+func (*okMethod) GetParamsType() interface{}                        { return nil }
+func (m *okMethod) GetName() string                                 { return m.name }
+func (*okMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return "ok", nil }
+
+type failingStore struct{}
+
+func (failingStore) Take(...Bucket) (bool, time.Duration, error) {
+	return false, 0, errors.New("store is unavailable")
+}
+
+func newTestRouter(t *testing.T, limiter *Limiter) *rpcRouter.Router {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethods(&okMethod{name: "foo"}, &okMethod{name: "bar"}))
+	assert.NoError(t, router.RegisterVersionedMethod("v1", &okMethod{name: "foo"}))
+	assert.NoError(t, router.RegisterAlias("foo.alias", "foo"))
+
+	router.Use(limiter.Middleware)
+
+	return router
+}
+
+func withRemoteAddr(addr string) context.Context {
+	return metadata.NewContext(context.Background(), &metadata.Metadata{RemoteAddr: addr})
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	store, _ := newTestStore()
+	limiter := New(store, ByIP)
+	limiter.Global = Limit{Count: 3, Period: time.Minute}
+	limiter.SetMethodLimit("foo", Limit{Count: 1, Period: time.Second})
+
+	var (
+		router = newTestRouter(t, limiter)
+		ctx    = withRemoteAddr("10.0.0.1:1234")
+	)
+
+	result, err := router.InvokeContext(ctx, "foo", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", result)
+
+	// method limit
+	_, err = router.InvokeContext(ctx, "foo", nil)
+	assert.Equal(t, int(rpcErrors.RateLimited), err.GetCode())
+	assert.Equal(t, map[string]int{"retry_after": 1}, err.GetData())
+
+	md, _ := metadata.FromContext(ctx)
+	assert.Equal(t, "1", md.ResponseHeader().Get("Retry-After"))
+
+	// other clients are not affected
+	_, err = router.InvokeContext(withRemoteAddr("10.0.0.2:1234"), "foo", nil)
+	assert.Nil(t, err)
+
+	// global limit (the second "foo" call was rejected by the method limit, so global token was not taken)
+	for i := 0; i < 2; i++ {
+		_, err = router.InvokeContext(ctx, "bar", nil)
+		assert.Nil(t, err, i)
+	}
+
+	_, err = router.InvokeContext(ctx, "bar", nil)
+	assert.Equal(t, int(rpcErrors.RateLimited), err.GetCode())
+	assert.Equal(t, map[string]int{"retry_after": 20}, err.GetData())
+
+	// method limit removing
+	limiter.SetMethodLimit("foo", Limit{})
+	limiter.Global = Limit{}
+
+	for i := 0; i < 5; i++ {
+		_, err = router.InvokeContext(ctx, "foo", nil)
+		assert.Nil(t, err, i)
+	}
+}
+
+func TestLimiter_MiddlewareAliasesAndVersions(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(NewMemoryStore(), ByIP)
+	limiter.SetMethodLimit("foo", Limit{Count: 1, Period: time.Hour})
+
+	var (
+		router = newTestRouter(t, limiter)
+		ctx    = withRemoteAddr("10.0.0.1:1234")
+	)
+
+	_, err := router.InvokeContext(ctx, "foo", nil)
+	assert.Nil(t, err)
+
+	for _, name := range []string{"foo", "foo.alias", "foo@v1"} {
+		_, err = router.InvokeContext(ctx, name, nil)
+		assert.Equal(t, int(rpcErrors.RateLimited), err.GetCode(), name)
+	}
+}
+
+func TestLimiter_MiddlewareGlobalLimitDoesNotTakeMethodToken(t *testing.T) {
+	t.Parallel()
+
+	store, now := newTestStore()
+	limiter := New(store, ByIP)
+	limiter.Global = Limit{Count: 1, Period: time.Second}
+	limiter.SetMethodLimit("foo", Limit{Count: 2, Period: time.Hour})
+
+	var (
+		router = newTestRouter(t, limiter)
+		ctx    = withRemoteAddr("10.0.0.1:1234")
+	)
+
+	_, err := router.InvokeContext(ctx, "foo", nil)
+	assert.Nil(t, err)
+
+	// rejected by the global limit, so the method token is not taken
+	_, err = router.InvokeContext(ctx, "foo", nil)
+	assert.Equal(t, int(rpcErrors.RateLimited), err.GetCode())
+
+	*now = now.Add(time.Second)
+
+	_, err = router.InvokeContext(ctx, "foo", nil)
+	assert.Nil(t, err)
+}
+
+func TestLimiter_MiddlewareStoreError(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(failingStore{}, ByIP)
+	limiter.Global = Limit{Count: 1, Period: time.Second}
+
+	_, err := newTestRouter(t, limiter).InvokeContext(context.Background(), "foo", nil)
+	assert.Equal(t, int(rpcErrors.Internal), err.GetCode())
+	assert.Equal(t, "store is unavailable", err.GetData())
+}
+
+func TestLimiter_Batch(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(NewMemoryStore(), ByPrincipal)
+	limiter.Global = Limit{Count: 2, Period: time.Hour}
+
+	var (
+		kernel = rpcKernel.New(newTestRouter(t, limiter))
+		ctx    = auth.NewContext(context.Background(), &auth.Principal{ID: "john"})
+	)
+
+	result := kernel.HandleJSONRequestContext(ctx, []byte(`[
+		{"jsonrpc": "2.0", "method": "foo", "id": 1},
+		{"jsonrpc": "2.0", "method": "foo", "id": 2},
+		{"jsonrpc": "2.0", "method": "bar", "id": 3}
+	]`))
+
+	var responses []map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(result, &responses))
+	assert.Len(t, responses, 3)
+
+	var limited int
+
+	for _, response := range responses {
+		if e, ok := response["error"].(map[string]interface{}); ok {
+			assert.Equal(t, float64(rpcErrors.RateLimited), e["code"])
+
+			limited++
+		}
+	}
+
+	assert.Equal(t, 1, limited)
+}
+
+func TestKeyFuncs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", ByIP(context.Background(), "foo"))
+	assert.Equal(t, "10.0.0.1", ByIP(withRemoteAddr("10.0.0.1:1234"), "foo"))
+	assert.Equal(t, "::1", ByIP(withRemoteAddr("[::1]:1234"), "foo"))
+	assert.Equal(t, "pipe", ByIP(withRemoteAddr("pipe"), "foo"))
+
+	assert.Equal(t, "ip:10.0.0.1", ByPrincipal(withRemoteAddr("10.0.0.1:1234"), "foo"))
+	assert.Equal(t, "principal:john", ByPrincipal(
+		auth.NewContext(withRemoteAddr("10.0.0.1:1234"), &auth.Principal{ID: "john"}), "foo",
+	))
+}
+
+func TestLimit_IsZero(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Limit{}.IsZero())
+	assert.True(t, Limit{Count: 1}.IsZero())
+	assert.True(t, Limit{Period: time.Second}.IsZero())
+	assert.False(t, Limit{Count: 1, Period: time.Second}.IsZero())
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket key and limit.
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Store stores token buckets.
+type Store interface {
+	// Take atomically takes one token from each of the buckets (bucket is created full, when it does not exist). When
+	// any bucket is empty, tokens are not taken from any bucket, and the time until all buckets have tokens is
+	// returned.
+	Take(buckets ...Bucket) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// DefaultSweepInterval is the default interval of the full (idle) buckets removal from the MemoryStore.
+const DefaultSweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     Limit
+}
+
+// MemoryStore is an in-memory token buckets store. Full buckets are removed (on taking) once per SweepInterval.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	sweptAt time.Time
+	now     func() time.Time
+
+	// SweepInterval is the interval of the full buckets removal (DefaultSweepInterval by default).
+	SweepInterval time.Duration
+}
+
+// NewMemoryStore creates new in-memory token buckets store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       make(map[string]*bucket),
+		sweptAt:       time.Now(),
+		now:           time.Now,
+		SweepInterval: DefaultSweepInterval,
+	}
+}
+
+// Take implements Store interface.
+func (s *MemoryStore) Take(buckets ...Bucket) (bool, time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var (
+		now        = s.now()
+		taken      = make([]*bucket, 0, len(buckets))
+		retryAfter time.Duration
+	)
+
+	if now.Sub(s.sweptAt) >= s.SweepInterval {
+		s.sweep(now)
+	}
+
+	for _, requested := range buckets {
+		b, ok := s.buckets[requested.Key]
+		if !ok || b.limit != requested.Limit {
+			b = &bucket{tokens: float64(requested.Limit.burst()), updatedAt: now, limit: requested.Limit}
+			s.buckets[requested.Key] = b
+		}
+
+		b.refill(now)
+
+		if b.tokens < 1 {
+			wait := time.Duration(math.Ceil((1 - b.tokens) / b.limit.rate() * float64(time.Second)))
+
+			if wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+
+		taken = append(taken, b)
+	}
+
+	if retryAfter > 0 {
+		return false, retryAfter, nil
+	}
+
+	for _, b := range taken {
+		b.tokens--
+	}
+
+	return true, 0, nil
+}
+
+// sweep removes full buckets. Must be called under lock.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.burst()) {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.sweptAt = now
+}
+
+// refill adds the tokens, generated since the last update.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.burst()), b.tokens+elapsed.Seconds()*b.limit.rate())
+		b.updatedAt = now
+	}
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore() (*MemoryStore, *time.Time) {
+	var (
+		store = NewMemoryStore()
+		now   = time.Unix(1600000000, 0)
+	)
+
+	store.now = func() time.Time { return now }
+	store.sweptAt = now
+
+	return store, &now
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store, now = newTestStore()
+		limit      = Limit{Count: 2, Period: time.Second, Burst: 3}
+	)
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := store.Take(Bucket{Key: "foo", Limit: limit})
+		assert.NoError(t, err)
+		assert.True(t, allowed, i)
+	}
+
+	allowed, retryAfter, err := store.Take(Bucket{Key: "foo", Limit: limit})
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// other keys are not affected
+	allowed, _, _ = store.Take(Bucket{Key: "bar", Limit: limit})
+	assert.True(t, allowed)
+
+	*now = now.Add(250 * time.Millisecond)
+
+	allowed, retryAfter, _ = store.Take(Bucket{Key: "foo", Limit: limit})
+	assert.False(t, allowed)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+
+	*now = now.Add(250 * time.Millisecond)
+
+	allowed, _, _ = store.Take(Bucket{Key: "foo", Limit: limit})
+	assert.True(t, allowed)
+
+	allowed, _, _ = store.Take(Bucket{Key: "foo", Limit: limit})
+	assert.False(t, allowed)
+
+	// bucket is not refilled above the burst
+	*now = now.Add(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		allowed, _, _ = store.Take(Bucket{Key: "foo", Limit: limit})
+		assert.True(t, allowed, i)
+	}
+
+	allowed, _, _ = store.Take(Bucket{Key: "foo", Limit: limit})
+	assert.False(t, allowed)
+
+	// changed limit resets the bucket
+	allowed, _, _ = store.Take(Bucket{Key: "foo", Limit: Limit{Count: 1, Period: time.Second}})
+	assert.True(t, allowed)
+}
+
+func TestMemoryStore_TakeMultiple(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store, now = newTestStore()
+		perSecond  = Bucket{Key: "foo", Limit: Limit{Count: 1, Period: time.Second}}
+		perMinute  = Bucket{Key: "bar", Limit: Limit{Count: 2, Period: time.Minute}}
+	)
+
+	allowed, _, err := store.Take(perSecond, perMinute)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	// tokens are not taken from any bucket, when one of them is empty
+	allowed, retryAfter, _ := store.Take(perSecond, perMinute)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+
+	allowed, _, _ = store.Take(perMinute)
+	assert.True(t, allowed)
+
+	*now = now.Add(time.Second)
+
+	// the longest wait is returned
+	allowed, retryAfter, _ = store.Take(perSecond, perMinute)
+	assert.False(t, allowed)
+	assert.Equal(t, 29*time.Second, retryAfter)
+
+	allowed, _, _ = store.Take(perSecond)
+	assert.True(t, allowed)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store, now = newTestStore()
+		limit      = Limit{Count: 1, Period: time.Hour}
+	)
+
+	_, _, _ = store.Take(Bucket{Key: "foo", Limit: Limit{Count: 1, Period: time.Second}})
+	_, _, _ = store.Take(Bucket{Key: "bar", Limit: limit})
+
+	*now = now.Add(DefaultSweepInterval)
+
+	_, _, _ = store.Take(Bucket{Key: "baz", Limit: limit})
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	assert.Len(t, store.buckets, 2) // "foo" is full, so it was removed
+	assert.NotContains(t, store.buckets, "foo")
+}
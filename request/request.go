@@ -14,12 +14,53 @@ type Request struct {
 	ID      interface{} `json:"id"`               // optional for notifications only, string|int
 }
 
+// Notification represents a JSON-RPC notification (request without ID), sent by the server to the client.
+type Notification struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 // Validate makes request validation (request is correct and can be processed?).
 func (request *Request) Validate() error {
 	if request.Version != jsonrpc.Version {
 		return errors.New("wrong version")
 	}
 
+	return request.validate()
+}
+
+// IsLegacy returns `true` when request uses legacy JsonRPC version (1.0 or 1.1).
+func (request *Request) IsLegacy() bool {
+	switch request.Version {
+	case "", jsonrpc.Version10, jsonrpc.Version11:
+		return true
+	}
+
+	return false
+}
+
+// ValidateLegacy makes request validation by the rules of legacy JsonRPC versions (1.0 and 1.1).
+func (request *Request) ValidateLegacy() error {
+	if !request.IsLegacy() {
+		return errors.New("wrong version")
+	}
+
+	// version 1.0 allows params as an array only
+	if request.Version != jsonrpc.Version11 {
+		switch request.Params.(type) {
+		case nil, []interface{}:
+			break
+		default:
+			return errors.New("wrong params type")
+		}
+	}
+
+	return request.validate()
+}
+
+// validate checks request properties (except version).
+func (request *Request) validate() error {
 	if request.Method == "" {
 		return errors.New("empty method")
 	}
@@ -113,6 +113,78 @@ func TestRequest_IsValid(t *testing.T) {
 	}
 }
 
+func TestRequest_ValidateLegacy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name               string
+		giveRequest        Request
+		wantErrorSubstring string
+	}{
+		{
+			name:        "valid 1.0",
+			giveRequest: Request{Method: "foo", Params: []interface{}{1, 2}, ID: 1},
+		},
+		{
+			name:        "valid 1.0 (notification)",
+			giveRequest: Request{Method: "foo", Params: []interface{}{1, 2}, ID: nil},
+		},
+		{
+			name:        "valid 1.0 (with version)",
+			giveRequest: Request{Version: "1.0", Method: "foo", Params: []interface{}{}, ID: "bar"},
+		},
+		{
+			name:               "1.0 with params as an object",
+			giveRequest:        Request{Method: "foo", Params: map[string]interface{}{}, ID: 1},
+			wantErrorSubstring: "wrong params type",
+		},
+		{
+			name:        "valid 1.1 with params as an object",
+			giveRequest: Request{Version: "1.1", Method: "foo", Params: map[string]interface{}{}, ID: 1},
+		},
+		{
+			name:               "1.1 with wrong params",
+			giveRequest:        Request{Version: "1.1", Method: "foo", Params: "bar", ID: 1},
+			wantErrorSubstring: "wrong params type",
+		},
+		{
+			name:               "2.0",
+			giveRequest:        Request{Version: "2.0", Method: "foo", ID: 1},
+			wantErrorSubstring: "wrong version",
+		},
+		{
+			name:               "empty method",
+			giveRequest:        Request{Method: "", ID: 1},
+			wantErrorSubstring: "empty method",
+		},
+		{
+			name:               "wrong ID type",
+			giveRequest:        Request{Method: "foo", ID: true},
+			wantErrorSubstring: "wrong id type",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.giveRequest.ValidateLegacy()
+
+			if tt.wantErrorSubstring == "" {
+				assert.Nil(t, result)
+			} else {
+				assert.EqualError(t, result, tt.wantErrorSubstring)
+			}
+		})
+	}
+}
+
+func TestRequest_IsLegacy(t *testing.T) {
+	t.Parallel()
+
+	for version, want := range map[string]bool{"": true, "1.0": true, "1.1": true, "2.0": false, "3": false} {
+		assert.Equal(t, want, (&Request{Version: version}).IsLegacy(), version)
+	}
+}
+
 func TestJsonMarshaling(t *testing.T) {
 	t.Parallel()
 
@@ -121,3 +193,12 @@ func TestJsonMarshaling(t *testing.T) {
 	assert.Nil(t, err)
 	assert.JSONEq(t, `{"jsonrpc":"1.2", "method":"foo", "params":[1,2], "id":"bar"}`, string(res))
 }
+
+func TestNotificationJsonMarshaling(t *testing.T) {
+	t.Parallel()
+
+	res, err := json.Marshal(Notification{Version: "2.0", Method: "foo", Params: []int{1, 2}})
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0", "method":"foo", "params":[1,2]}`, string(res))
+}
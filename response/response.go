@@ -3,6 +3,7 @@ package response
 import (
 	"sync"
 
+	"github.com/tarampampam/go-jsonrpc"
 	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
 )
 
@@ -15,6 +16,21 @@ type (
 		ID      interface{}      `json:"id,omitempty"`     // optional for errors, string|int
 	}
 
+	// ResponseV10 represents a JSON-RPC 1.0 response (result and error are always present).
+	ResponseV10 struct {
+		Result interface{}      `json:"result"`
+		Error  *rpcErrors.Error `json:"error"`
+		ID     interface{}      `json:"id"`
+	}
+
+	// ResponseV11 represents a JSON-RPC 1.1 response.
+	ResponseV11 struct {
+		Version string           `json:"version"`
+		Result  interface{}      `json:"result,omitempty"`
+		Error   *rpcErrors.Error `json:"error,omitempty"`
+		ID      interface{}      `json:"id"`
+	}
+
 	// Responses collects set of responses
 	Responses struct {
 		mutex sync.RWMutex
@@ -22,6 +38,20 @@ type (
 	}
 )
 
+// Versioned returns response in the structure of its JsonRPC version (the response itself will be returned for the
+// version 2.0).
+func (response Response) Versioned() interface{} {
+	switch response.Version {
+	case jsonrpc.Version10:
+		return ResponseV10{Result: response.Result, Error: response.Error, ID: response.ID}
+
+	case jsonrpc.Version11:
+		return ResponseV11{Version: response.Version, Result: response.Result, Error: response.Error, ID: response.ID}
+	}
+
+	return response
+}
+
 // NewResponses creates new responses collection
 func NewResponses() *Responses {
 	return &Responses{
@@ -27,6 +27,53 @@ func TestJsonMarshalingError(t *testing.T) {
 	assert.JSONEq(t, `{"jsonrpc":"1.2", "error":{"code":1, "message":"foo"}, "id":"bar"}`, string(res))
 }
 
+func TestResponse_Versioned(t *testing.T) {
+	t.Parallel()
+
+	var result interface{} = "foo"
+
+	cases := []struct {
+		name     string
+		give     Response
+		wantJSON string
+	}{
+		{
+			name:     "2.0",
+			give:     Response{Version: "2.0", Result: &result, ID: 1},
+			wantJSON: `{"jsonrpc":"2.0", "result":"foo", "id":1}`,
+		},
+		{
+			name:     "1.0 result",
+			give:     Response{Version: "1.0", Result: &result, ID: 1},
+			wantJSON: `{"result":"foo", "error":null, "id":1}`,
+		},
+		{
+			name:     "1.0 error",
+			give:     Response{Version: "1.0", Error: &rpcErrors.Error{Code: 1, Message: "foo"}},
+			wantJSON: `{"result":null, "error":{"code":1, "message":"foo"}, "id":null}`,
+		},
+		{
+			name:     "1.1 result",
+			give:     Response{Version: "1.1", Result: &result, ID: "bar"},
+			wantJSON: `{"version":"1.1", "result":"foo", "id":"bar"}`,
+		},
+		{
+			name:     "1.1 error",
+			give:     Response{Version: "1.1", Error: &rpcErrors.Error{Code: 1, Message: "foo"}, ID: "bar"},
+			wantJSON: `{"version":"1.1", "error":{"code":1, "message":"foo"}, "id":"bar"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := json.Marshal(tt.give.Versioned())
+
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(res))
+		})
+	}
+}
+
 func TestNewResponsesAndAdd(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,239 @@
+// Package rest provides REST-to-RPC HTTP gateway, that maps HTTP routes (e.g. `GET /users/{id}`) to the Router
+// methods.
+package rest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+type (
+	// Annotated is an optional jsonrpc.Method interface for REST routes declaration (in format `GET /users/{id}`).
+	Annotated interface {
+		RESTRoutes() []string
+	}
+
+	// methodsLister is implemented by the default router.
+	methodsLister interface {
+		MethodNames() []string
+		LookupMethod(methodName string) (jsonrpc.Method, bool)
+	}
+
+	errorResponse struct {
+		Error *rpcErrors.Error `json:"error"`
+	}
+)
+
+// Gateway is an HTTP handler, that invokes the Router methods by the REST routes.
+type Gateway struct {
+	router jsonrpc.Router
+	json   jsoniter.API
+	mutex  sync.RWMutex
+	routes []Route
+
+	// StatusCodes (optional) maps RPC error codes into HTTP status codes for all routes.
+	StatusCodes map[rpcErrors.Code]int
+
+	// MaxBodyBytes limits request body size (zero means "without limit").
+	MaxBodyBytes int64
+}
+
+// New creates new REST gateway for the router.
+func New(router jsonrpc.Router) *Gateway {
+	return &Gateway{
+		router: router,
+		json:   jsoniter.ConfigFastest,
+		routes: make([]Route, 0),
+	}
+}
+
+// Handle registers new route. Routes are matched in the order of registration.
+func (gateway *Gateway) Handle(route Route) error {
+	if err := route.compile(); err != nil {
+		return err
+	}
+
+	gateway.mutex.Lock()
+	defer gateway.mutex.Unlock()
+
+	for _, r := range gateway.routes {
+		if r.HTTPMethod == route.HTTPMethod && r.Pattern == route.Pattern {
+			return errors.New("rest: route " + route.HTTPMethod + " " + route.Pattern + " is already registered")
+		}
+	}
+
+	gateway.routes = append(gateway.routes, route)
+
+	return nil
+}
+
+// HandleAnnotated registers routes, declared by the router methods (see Annotated interface). Router must
+// implement `MethodNames` and `LookupMethod` methods (as the default router does).
+func (gateway *Gateway) HandleAnnotated() error {
+	lister, ok := gateway.router.(methodsLister)
+	if !ok {
+		return errors.New("rest: router does not support methods listing")
+	}
+
+	for _, name := range lister.MethodNames() {
+		method, _ := lister.LookupMethod(name)
+
+		annotated, ok := method.(Annotated)
+		if !ok {
+			continue
+		}
+
+		for _, annotation := range annotated.RESTRoutes() {
+			route, err := ParseRoute(annotation, name)
+			if err != nil {
+				return err
+			}
+
+			if err = gateway.Handle(route); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler interface.
+func (gateway *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w, r = metadata.WrapHTTP(w, r)
+
+	route, vars, status := gateway.match(r)
+
+	if status != http.StatusOK {
+		if status == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", route.HTTPMethod)
+		}
+
+		gateway.writeError(w, status, rpcErrors.New(rpcErrors.MethodNotFound))
+
+		return
+	}
+
+	if gateway.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, gateway.MaxBodyBytes)
+	}
+
+	var body []byte
+
+	if r.Body != nil { // body is always non-nil for the server requests
+		var readErr error
+
+		if body, readErr = ioutil.ReadAll(r.Body); readErr != nil {
+			err := rpcErrors.New(rpcErrors.InvalidRequest)
+			err.Data = readErr.Error()
+
+			gateway.writeError(w, http.StatusRequestEntityTooLarge, err)
+
+			return
+		}
+	}
+
+	params, paramsErr := buildParams(gateway.json, r.URL.Query(), body, vars)
+	if paramsErr != nil {
+		err := rpcErrors.New(rpcErrors.InvalidRequest)
+		err.Data = paramsErr.Error()
+
+		gateway.writeError(w, gateway.statusCode(route, err.Code), err)
+
+		return
+	}
+
+	result, invokeErr := gateway.invoke(r.Context(), route.Method, params)
+	if invokeErr != nil {
+		err := &rpcErrors.Error{
+			Code:    rpcErrors.Code(invokeErr.GetCode()),
+			Message: invokeErr.GetMessage(),
+			Data:    invokeErr.GetData(),
+		}
+
+		gateway.writeError(w, gateway.statusCode(route, err.Code), err)
+
+		return
+	}
+
+	gateway.write(w, route.SuccessStatus, result)
+}
+
+// match looks for the route by the request method and path. When path was matched, but HTTP method - not, the
+// status 405 will be returned (and 404 - when nothing was matched).
+func (gateway *Gateway) match(r *http.Request) (Route, map[string]interface{}, int) {
+	gateway.mutex.RLock()
+	defer gateway.mutex.RUnlock()
+
+	var (
+		status  = http.StatusNotFound
+		allowed Route
+	)
+
+	for _, route := range gateway.routes {
+		if vars, ok := route.match(r.URL.Path); ok {
+			if route.HTTPMethod == r.Method {
+				return route, vars, http.StatusOK
+			}
+
+			status, allowed = http.StatusMethodNotAllowed, route
+		}
+	}
+
+	return allowed, nil, status
+}
+
+// statusCode returns HTTP status code for the RPC error code using route, method, gateway and default mappings.
+func (gateway *Gateway) statusCode(route Route, code rpcErrors.Code) int {
+	if status, ok := route.StatusCodes[code]; ok {
+		return status
+	}
+
+	if lister, ok := gateway.router.(methodsLister); ok {
+		if method, found := lister.LookupMethod(route.Method); found {
+			if coder, ok := method.(StatusCoder); ok {
+				if status, ok := coder.HTTPStatusCodes()[code]; ok {
+					return status
+				}
+			}
+		}
+	}
+
+	if status, ok := gateway.StatusCodes[code]; ok {
+		return status
+	}
+
+	return StatusCode(code)
+}
+
+func (gateway *Gateway) invoke(ctx context.Context, method string, params interface{}) (interface{}, jsonrpc.Error) {
+	if router, ok := gateway.router.(jsonrpc.ContextRouter); ok {
+		return router.InvokeContext(ctx, method, params)
+	}
+
+	return gateway.router.Invoke(method, params)
+}
+
+func (gateway *Gateway) writeError(w http.ResponseWriter, status int, err *rpcErrors.Error) {
+	gateway.write(w, status, errorResponse{Error: err})
+}
+
+func (gateway *Gateway) write(w http.ResponseWriter, status int, body interface{}) {
+	data, err := gateway.json.Marshal(body)
+	if err != nil {
+		status, data = http.StatusInternalServerError, []byte(`{"error":{"code":-32603,"message":"Internal error"}}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_, _ = w.Write(data)
+}
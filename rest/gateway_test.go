@@ -0,0 +1,265 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type (
+	userGetMethod       struct{}
+	userGetMethodParams struct {
+		ID     int  `json:"id"`
+		Detail bool `json:"detail"`
+	}
+)
+
+func (*userGetMethod) GetParamsType() interface{} { return &userGetMethodParams{} }
+func (*userGetMethod) GetName() string            { return "get" }
+func (*userGetMethod) RESTRoutes() []string       { return []string{"GET /users/{id:int}"} }
+func (*userGetMethod) HTTPStatusCodes() map[rpcErrors.Code]int {
+	return map[rpcErrors.Code]int{1: http.StatusNotFound}
+}
+func (*userGetMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	p := params.(*userGetMethodParams)
+
+	if p.ID != 1 {
+		return nil, &rpcErrors.Error{Code: 1, Message: "user not found"}
+	}
+
+	return map[string]interface{}{"id": p.ID, "detail": p.Detail}, nil
+}
+
+type (
+	orderCreateMethod       struct{}
+	orderCreateMethodParams struct {
+		Item  string `json:"item"`
+		Count int    `json:"count"`
+	}
+)
+
+func (*orderCreateMethod) GetParamsType() interface{} { return &orderCreateMethodParams{} }
+func (*orderCreateMethod) GetName() string            { return "order.create" }
+func (*orderCreateMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	p := params.(*orderCreateMethodParams)
+
+	if p.Count <= 0 {
+		return nil, &rpcErrors.Error{Code: 2, Message: "wrong count"}
+	}
+
+	return p, nil
+}
+
+type wrongAnnotatedMethod struct{}
+
+func (*wrongAnnotatedMethod) GetParamsType() interface{}                        { return nil }
+func (*wrongAnnotatedMethod) GetName() string                                   { return "wrong" }
+func (*wrongAnnotatedMethod) RESTRoutes() []string                              { return []string{"GET"} }
+func (*wrongAnnotatedMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return nil, nil }
+
+func newTestGateway(t *testing.T) *Gateway {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.Group("user").RegisterMethod(&userGetMethod{}))
+	assert.NoError(t, router.RegisterMethod(&orderCreateMethod{}))
+
+	gateway := New(router)
+	gateway.StatusCodes = map[rpcErrors.Code]int{2: http.StatusUnprocessableEntity}
+
+	assert.NoError(t, gateway.HandleAnnotated())
+	assert.NoError(t, gateway.Handle(Route{
+		HTTPMethod:    http.MethodPost,
+		Pattern:       "/orders",
+		Method:        "order.create",
+		SuccessStatus: http.StatusCreated,
+	}))
+	assert.NoError(t, gateway.Handle(Route{
+		HTTPMethod:  http.MethodPost,
+		Pattern:     "/orders/strict",
+		Method:      "order.create",
+		StatusCodes: map[rpcErrors.Code]int{2: http.StatusConflict},
+	}))
+	assert.NoError(t, gateway.Handle(Route{
+		HTTPMethod:    http.MethodPost,
+		Pattern:       "/orders/{item}",
+		Method:        "order.create",
+		SuccessStatus: http.StatusCreated,
+	}))
+	assert.NoError(t, gateway.Handle(Route{HTTPMethod: http.MethodGet, Pattern: "/missing", Method: "missing"}))
+
+	return gateway
+}
+
+func TestGateway_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+
+	cases := []struct {
+		name       string
+		giveMethod string
+		giveURL    string
+		giveBody   string
+		wantCode   int
+		wantJSON   string
+		wantAllow  string
+	}{
+		{
+			name:       "path variables and query",
+			giveMethod: http.MethodGet,
+			giveURL:    "/users/1?detail=true",
+			wantCode:   http.StatusOK,
+			wantJSON:   `{"id": 1, "detail": true}`,
+		},
+		{
+			name:       "error code from the method metadata",
+			giveMethod: http.MethodGet,
+			giveURL:    "/users/2",
+			wantCode:   http.StatusNotFound,
+			wantJSON:   `{"error": {"code": 1, "message": "user not found"}}`,
+		},
+		{
+			name:       "invalid params",
+			giveMethod: http.MethodGet,
+			giveURL:    "/users/1?detail=foo",
+			wantCode:   http.StatusBadRequest,
+			wantJSON:   `{"error": {"code": -32602, "message": "Invalid params"}}`,
+		},
+		{
+			name:       "path variable of the wrong type",
+			giveMethod: http.MethodGet,
+			giveURL:    "/users/foo",
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "path variable without type is a string",
+			giveMethod: http.MethodPost,
+			giveURL:    "/orders/007",
+			giveBody:   `{"count": 1}`,
+			wantCode:   http.StatusCreated,
+			wantJSON:   `{"item": "007", "count": 1}`,
+		},
+		{
+			name:       "JSON body",
+			giveMethod: http.MethodPost,
+			giveURL:    "/orders",
+			giveBody:   `{"item": "apple", "count": 2}`,
+			wantCode:   http.StatusCreated,
+			wantJSON:   `{"item": "apple", "count": 2}`,
+		},
+		{
+			name:       "error code from the gateway",
+			giveMethod: http.MethodPost,
+			giveURL:    "/orders",
+			giveBody:   `{"item": "apple"}`,
+			wantCode:   http.StatusUnprocessableEntity,
+			wantJSON:   `{"error": {"code": 2, "message": "wrong count"}}`,
+		},
+		{
+			name:       "error code from the route",
+			giveMethod: http.MethodPost,
+			giveURL:    "/orders/strict",
+			giveBody:   `{"item": "apple"}`,
+			wantCode:   http.StatusConflict,
+			wantJSON:   `{"error": {"code": 2, "message": "wrong count"}}`,
+		},
+		{
+			name:       "wrong JSON body",
+			giveMethod: http.MethodPost,
+			giveURL:    "/orders",
+			giveBody:   `{"item"`,
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:       "unknown route",
+			giveMethod: http.MethodGet,
+			giveURL:    "/foo",
+			wantCode:   http.StatusNotFound,
+			wantJSON:   `{"error": {"code": -32601, "message": "Method not found"}}`,
+		},
+		{
+			name:       "wrong HTTP method",
+			giveMethod: http.MethodDelete,
+			giveURL:    "/orders",
+			wantCode:   http.StatusMethodNotAllowed,
+			wantAllow:  http.MethodPost,
+		},
+		{
+			name:       "route for the unregistered method",
+			giveMethod: http.MethodGet,
+			giveURL:    "/missing",
+			wantCode:   http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				req, _ = http.NewRequest(tt.giveMethod, "http://rest"+tt.giveURL, strings.NewReader(tt.giveBody))
+				rr     = httptest.NewRecorder()
+			)
+
+			gateway.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantAllow, rr.Header().Get("Allow"))
+
+			if tt.wantJSON != "" {
+				assert.JSONEq(t, tt.wantJSON, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGateway_MaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	gateway := newTestGateway(t)
+	gateway.MaxBodyBytes = 5
+
+	var (
+		req, _ = http.NewRequest(http.MethodPost, "http://rest/orders", strings.NewReader(`{"item": "apple"}`))
+		rr     = httptest.NewRecorder()
+	)
+
+	gateway.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
+
+func TestGateway_Handle(t *testing.T) {
+	t.Parallel()
+
+	gateway := New(rpcRouter.New())
+
+	assert.NoError(t, gateway.Handle(Route{Pattern: "/foo", Method: "foo"}))
+	assert.Contains(t, gateway.Handle(Route{Pattern: "/foo", Method: "bar"}).Error(), "already registered")
+	assert.NoError(t, gateway.Handle(Route{HTTPMethod: http.MethodPost, Pattern: "/foo", Method: "bar"}))
+	assert.Error(t, gateway.Handle(Route{Pattern: "foo", Method: "bar"}))
+}
+
+type plainRouter struct {
+	jsonrpc.Router
+}
+
+func TestGateway_HandleAnnotated(t *testing.T) {
+	t.Parallel()
+
+	assert.Contains(t, New(plainRouter{}).HandleAnnotated().Error(), "listing")
+
+	router := rpcRouter.New()
+	assert.NoError(t, router.RegisterMethod(&wrongAnnotatedMethod{}))
+	assert.Contains(t, New(router).HandleAnnotated().Error(), "wrong route annotation")
+
+	router = rpcRouter.New()
+	assert.NoError(t, router.RegisterMethod(&userGetMethod{}))
+	assert.NoError(t, router.Group("v2").RegisterMethod(&userGetMethod{}))
+	assert.Contains(t, New(router).HandleAnnotated().Error(), "already registered")
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// buildParams builds RPC method params from the query string, JSON body and path variables (in order of the
+// precedence increasing). Body, that is not a JSON object, is passed as-is when there are no other params.
+func buildParams(json jsoniter.API, query url.Values, body []byte, vars map[string]interface{}) (interface{}, error) {
+	params := make(map[string]interface{})
+
+	for key, values := range query {
+		if len(values) == 1 {
+			params[key] = parseScalar(values[0])
+
+			continue
+		}
+
+		list := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			list = append(list, parseScalar(value))
+		}
+
+		params[key] = list
+	}
+
+	if len(body) > 0 {
+		var decoded interface{}
+
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			return nil, errors.New("rest: wrong JSON body: " + err.Error())
+		}
+
+		switch b := decoded.(type) {
+		case map[string]interface{}:
+			for key, value := range b {
+				params[key] = value
+			}
+
+		default:
+			if len(params) == 0 && len(vars) == 0 {
+				return decoded, nil
+			}
+
+			return nil, errors.New("rest: JSON body must be an object")
+		}
+	}
+
+	for key, value := range vars {
+		params[key] = value
+	}
+
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	return params, nil
+}
+
+// parseScalar converts query string value into int64, float64 or bool, if the string is a canonical representation
+// of the value (so "007" stays a string, but "7" becomes a number).
+func parseScalar(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+		return i
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == s {
+		return f
+	}
+
+	if b, err := strconv.ParseBool(s); err == nil && strconv.FormatBool(b) == s {
+		return b
+	}
+
+	return s
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"net/url"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildParams(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		giveQuery  string
+		giveBody   string
+		giveVars   map[string]interface{}
+		wantParams interface{}
+		wantErr    bool
+	}{
+		{
+			name: "nothing",
+		},
+		{
+			name:      "query only",
+			giveQuery: "a=1&b=foo&c=true&d=1.5&e=007&list=1&list=x",
+			wantParams: map[string]interface{}{
+				"a": int64(1), "b": "foo", "c": true, "d": 1.5, "e": "007", "list": []interface{}{int64(1), "x"},
+			},
+		},
+		{
+			name:       "precedence",
+			giveQuery:  "id=1&a=query&b=query",
+			giveBody:   `{"id": 2, "b": "body", "c": "body"}`,
+			giveVars:   map[string]interface{}{"id": "3"},
+			wantParams: map[string]interface{}{"id": "3", "a": "query", "b": "body", "c": "body"},
+		},
+		{
+			name:       "body as an array",
+			giveBody:   `[1, 2]`,
+			wantParams: []interface{}{float64(1), float64(2)},
+		},
+		{
+			name:     "body as an array with path variables",
+			giveBody: `[1, 2]`,
+			giveVars: map[string]interface{}{"id": "3"},
+			wantErr:  true,
+		},
+		{
+			name:     "wrong json",
+			giveBody: `{`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.giveQuery)
+
+			params, err := buildParams(jsoniter.ConfigFastest, query, []byte(tt.giveBody), tt.giveVars)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestParseScalar(t *testing.T) {
+	t.Parallel()
+
+	for give, want := range map[string]interface{}{
+		"1":     int64(1),
+		"-5":    int64(-5),
+		"01":    "01",
+		"1.25":  1.25,
+		"1e3":   "1e3",
+		"true":  true,
+		"false": false,
+		"TRUE":  "TRUE",
+		"":      "",
+		"foo":   "foo",
+	} {
+		assert.Equal(t, want, parseScalar(give), give)
+	}
+}
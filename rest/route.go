@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// Route maps HTTP method and path pattern to the RPC method.
+type Route struct {
+	// HTTPMethod is an HTTP method (e.g. `GET`).
+	HTTPMethod string
+
+	// Pattern is a path pattern with variables in braces (e.g. `/users/{id}`). Variables values are passed as
+	// strings, unless the variable type is set (`int`, `float` or `bool`, e.g. `/users/{id:int}`). Path, that
+	// contains the value of the wrong type, is not matched.
+	Pattern string
+
+	// Method is RPC method name.
+	Method string
+
+	// SuccessStatus is HTTP status code for the successful responses (200 by default).
+	SuccessStatus int
+
+	// StatusCodes (optional) maps RPC error codes into HTTP status codes for this route only.
+	StatusCodes map[rpcErrors.Code]int
+
+	segments []string
+}
+
+// ParseRoute parses route annotation in format `HTTP_METHOD /path/{var}` (e.g. `GET /users/{id}`).
+func ParseRoute(annotation, methodName string) (Route, error) {
+	fields := strings.Fields(annotation)
+
+	if len(fields) != 2 { //nolint:gomnd
+		return Route{}, errors.New("rest: wrong route annotation format: " + annotation)
+	}
+
+	return Route{HTTPMethod: fields[0], Pattern: fields[1], Method: methodName}, nil
+}
+
+// compile validates route and prepares it for matching.
+func (route *Route) compile() error {
+	if route.HTTPMethod == "" {
+		route.HTTPMethod = http.MethodGet
+	}
+
+	route.HTTPMethod = strings.ToUpper(route.HTTPMethod)
+
+	if route.Method == "" {
+		return errors.New("rest: route method should not be empty")
+	}
+
+	if !strings.HasPrefix(route.Pattern, "/") {
+		return errors.New("rest: route pattern must start with a slash: " + route.Pattern)
+	}
+
+	route.segments = splitPath(route.Pattern)
+	seen := make(map[string]struct{})
+
+	for _, segment := range route.segments {
+		if name, kind, ok := variable(segment); ok {
+			if name == "" {
+				return errors.New("rest: empty variable name in the pattern " + route.Pattern)
+			}
+
+			if _, known := convertVariable(kind, "0"); !known {
+				return errors.New("rest: unknown variable " + name + " type " + kind + " in the pattern " + route.Pattern)
+			}
+
+			if _, duplicated := seen[name]; duplicated {
+				return errors.New("rest: duplicated variable " + name + " in the pattern " + route.Pattern)
+			}
+
+			seen[name] = struct{}{}
+		}
+	}
+
+	if route.SuccessStatus == 0 {
+		route.SuccessStatus = http.StatusOK
+	}
+
+	return nil
+}
+
+// match checks the path against route pattern and returns path variables values.
+func (route *Route) match(path string) (map[string]interface{}, bool) {
+	parts := splitPath(path)
+
+	if len(parts) != len(route.segments) {
+		return nil, false
+	}
+
+	vars := make(map[string]interface{})
+
+	for i, segment := range route.segments {
+		if name, kind, ok := variable(segment); ok {
+			if parts[i] == "" {
+				return nil, false
+			}
+
+			value, converted := convertVariable(kind, parts[i])
+			if !converted {
+				return nil, false
+			}
+
+			vars[name] = value
+
+			continue
+		}
+
+		if segment != parts[i] {
+			return nil, false
+		}
+	}
+
+	return vars, true
+}
+
+// variable returns the name and the type of the pattern variable (type is empty for the string variables).
+func variable(segment string) (name, kind string, ok bool) {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+		return "", "", false
+	}
+
+	name = segment[1 : len(segment)-1]
+
+	if i := strings.Index(name, ":"); i >= 0 {
+		name, kind = name[:i], name[i+1:]
+	}
+
+	return name, kind, true
+}
+
+// convertVariable converts the path variable value into the variable type. It returns false, when the value or the
+// type is wrong.
+func convertVariable(kind, value string) (interface{}, bool) {
+	switch kind {
+	case "":
+		return value, true
+
+	case "int":
+		i, err := strconv.ParseInt(value, 10, 64)
+
+		return i, err == nil
+
+	case "float":
+		f, err := strconv.ParseFloat(value, 64)
+
+		return f, err == nil
+
+	case "bool":
+		b, err := strconv.ParseBool(value)
+
+		return b, err == nil
+	}
+
+	return nil, false
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, "/")
+}
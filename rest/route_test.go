@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoute(t *testing.T) {
+	t.Parallel()
+
+	route, err := ParseRoute("GET /users/{id}", "user.get")
+	assert.NoError(t, err)
+	assert.Equal(t, Route{HTTPMethod: "GET", Pattern: "/users/{id}", Method: "user.get"}, route)
+
+	_, err = ParseRoute("/users/{id}", "user.get")
+	assert.Error(t, err)
+}
+
+func TestRoute_compile(t *testing.T) {
+	t.Parallel()
+
+	route := Route{Pattern: "/users/{id}/orders/{order}", Method: "foo"}
+	assert.NoError(t, route.compile())
+	assert.Equal(t, http.MethodGet, route.HTTPMethod)
+	assert.Equal(t, http.StatusOK, route.SuccessStatus)
+	assert.Equal(t, []string{"users", "{id}", "orders", "{order}"}, route.segments)
+
+	route = Route{HTTPMethod: "post", Pattern: "/", Method: "foo", SuccessStatus: http.StatusCreated}
+	assert.NoError(t, route.compile())
+	assert.Equal(t, http.MethodPost, route.HTTPMethod)
+	assert.Equal(t, http.StatusCreated, route.SuccessStatus)
+
+	for _, wrong := range []Route{
+		{Pattern: "/foo"},
+		{Pattern: "foo", Method: "foo"},
+		{Pattern: "/foo/{}", Method: "foo"},
+		{Pattern: "/foo/{id}/{id}", Method: "foo"},
+		{Pattern: "/foo/{id:uuid}", Method: "foo"},
+	} {
+		assert.Error(t, wrong.compile(), wrong.Pattern)
+	}
+}
+
+func TestRoute_match(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern, path string
+		wantOk        bool
+		wantVars      map[string]interface{}
+	}{
+		{pattern: "/users/{id}", path: "/users/5", wantOk: true, wantVars: map[string]interface{}{"id": "5"}},
+		{pattern: "/users/{id}", path: "/users/5/", wantOk: true, wantVars: map[string]interface{}{"id": "5"}},
+		{pattern: "/users/{id}", path: "/users/007", wantOk: true, wantVars: map[string]interface{}{"id": "007"}},
+		{pattern: "/a/{x}/b/{y}", path: "/a/1/b/2", wantOk: true, wantVars: map[string]interface{}{"x": "1", "y": "2"}},
+		{pattern: "/", path: "/", wantOk: true, wantVars: map[string]interface{}{}},
+		{pattern: "/users", path: "/users", wantOk: true, wantVars: map[string]interface{}{}},
+		{pattern: "/users/{id:int}", path: "/users/5", wantOk: true, wantVars: map[string]interface{}{"id": int64(5)}},
+		{pattern: "/x/{f:float}", path: "/x/1.5", wantOk: true, wantVars: map[string]interface{}{"f": 1.5}},
+		{pattern: "/x/{b:bool}", path: "/x/true", wantOk: true, wantVars: map[string]interface{}{"b": true}},
+		{pattern: "/users/{id:int}", path: "/users/foo"},
+		{pattern: "/x/{b:bool}", path: "/x/yes"},
+		{pattern: "/users/{id}", path: "/users"},
+		{pattern: "/users/{id}", path: "/users//"},
+		{pattern: "/users/{id}", path: "/orders/5"},
+		{pattern: "/users/{id}", path: "/users/5/orders"},
+	}
+
+	for _, tt := range cases {
+		route := Route{Pattern: tt.pattern, Method: "foo"}
+		assert.NoError(t, route.compile())
+
+		vars, ok := route.match(tt.path)
+		assert.Equal(t, tt.wantOk, ok, tt.path)
+		assert.Equal(t, tt.wantVars, vars, tt.path)
+	}
+}
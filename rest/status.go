@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// StatusClientClosedRequest is a non-standard (nginx) HTTP status code for the requests, cancelled by the client.
+const StatusClientClosedRequest = 499
+
+// StatusCoder is an optional jsonrpc.Method interface, that allows method to declare own mapping of RPC error codes
+// into HTTP status codes.
+type StatusCoder interface {
+	HTTPStatusCodes() map[rpcErrors.Code]int
+}
+
+// StatusCode returns default HTTP status code for the RPC error code.
+func StatusCode(code rpcErrors.Code) int {
+	switch code {
+	case rpcErrors.Parse, rpcErrors.InvalidRequest, rpcErrors.InvalidParams:
+		return http.StatusBadRequest
+	case rpcErrors.MethodNotFound:
+		return http.StatusNotFound
+	case rpcErrors.Unauthorized:
+		return http.StatusUnauthorized
+	case rpcErrors.Forbidden:
+		return http.StatusForbidden
+	case rpcErrors.RateLimited:
+		return http.StatusTooManyRequests
+	case rpcErrors.Timeout:
+		return http.StatusGatewayTimeout
+	case rpcErrors.RequestCancelled:
+		return StatusClientClosedRequest
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	for code, want := range map[rpcErrors.Code]int{
+		rpcErrors.Parse:            http.StatusBadRequest,
+		rpcErrors.InvalidRequest:   http.StatusBadRequest,
+		rpcErrors.InvalidParams:    http.StatusBadRequest,
+		rpcErrors.MethodNotFound:   http.StatusNotFound,
+		rpcErrors.Internal:         http.StatusInternalServerError,
+		rpcErrors.Timeout:          http.StatusGatewayTimeout,
+		rpcErrors.Unauthorized:     http.StatusUnauthorized,
+		rpcErrors.Forbidden:        http.StatusForbidden,
+		rpcErrors.RateLimited:      http.StatusTooManyRequests,
+		rpcErrors.RequestCancelled: StatusClientClosedRequest,
+		-32000:                     http.StatusInternalServerError,
+		1:                          http.StatusInternalServerError,
+	} {
+		assert.Equal(t, want, StatusCode(code), code)
+	}
+}
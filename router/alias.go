@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tarampampam/go-jsonrpc"
+)
+
+// VersionSeparator separates method name and its version (e.g.: `user.get@v2`).
+const VersionSeparator = "@"
+
+type alias struct {
+	target     string
+	deprecated bool
+}
+
+type versionCtxKey struct{}
+
+// RegisterAlias registers an alias for the method (method can be registered later, in the current router or in the
+// mounted sub-router). Alias cannot have the name of a registered method, and existing alias is overwritten, if
+// DisallowDuplicates is not set.
+func (router *Router) RegisterAlias(aliasName, methodName string) error {
+	return router.registerAlias(aliasName, alias{target: methodName})
+}
+
+// RegisterDeprecatedAlias works like RegisterAlias, but DeprecatedCallHandler will be called on each alias using.
+func (router *Router) RegisterDeprecatedAlias(aliasName, methodName string) error {
+	return router.registerAlias(aliasName, alias{target: methodName, deprecated: true})
+}
+
+func (router *Router) registerAlias(aliasName string, a alias) error {
+	if aliasName == "" || a.target == "" {
+		return errEmptyMethodName
+	}
+
+	if aliasName == a.target {
+		return errors.New("jsonrpc: alias cannot point to itself")
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	// alias is resolved before the methods, so it would shadow the method with the same name
+	if _, exists := router.methods[aliasName]; exists {
+		return fmt.Errorf("%w: %s", ErrMethodAlreadyRegistered, aliasName)
+	}
+
+	if _, exists := router.aliases[aliasName]; exists && router.DisallowDuplicates {
+		return fmt.Errorf("%w: %s (alias)", ErrMethodAlreadyRegistered, aliasName)
+	}
+
+	router.aliases[aliasName] = a
+
+	return nil
+}
+
+// RegisterVersionedMethod registers method with the version suffix (`name@version`). Method can be invoked using
+// full name (`user.get@v2`), or without version - in this case version from the context (see WithVersion) or
+// router DefaultVersion will be used (and when the version was not found - method without version is invoked).
+func (router *Router) RegisterVersionedMethod(version string, method jsonrpc.Method) error {
+	var methodName = method.GetName()
+
+	if methodName == "" {
+		return errEmptyMethodName
+	}
+
+	if version == "" {
+		return errors.New("jsonrpc: method version should not be empty")
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	var versionedName = methodName + VersionSeparator + version
+
+	if _, exists := router.methods[versionedName]; exists && router.DisallowDuplicates {
+		return fmt.Errorf("%w: %s", ErrMethodAlreadyRegistered, versionedName)
+	}
+
+	router.methods[versionedName] = method
+
+	return nil
+}
+
+// WithVersion returns a context with methods version, that should be used for the methods resolving.
+func WithVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, versionCtxKey{}, version)
+}
+
+// VersionFromContext returns methods version, attached to the context using WithVersion.
+func VersionFromContext(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(versionCtxKey{}).(string)
+
+	return version, ok
+}
@@ -0,0 +1,179 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+func TestRouter_RegisterAlias(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"user.fetch"}))
+	assert.NoError(t, router.RegisterAlias("user.get", "user.fetch"))
+	assert.NoError(t, router.RegisterAlias("profile.get", "users.fetch")) // target can be registered later
+
+	assert.Error(t, router.RegisterAlias("", "user.fetch"))
+	assert.Error(t, router.RegisterAlias("user.get", ""))
+	assert.Contains(t, router.RegisterAlias("foo", "foo").Error(), "itself")
+
+	res, err := router.Invoke("user.get", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "user.fetch", res)
+	assert.True(t, router.MethodIsRegistered("user.get"))
+
+	_, err = router.Invoke("profile.get", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+
+	assert.NoError(t, router.Group("users").RegisterMethod(&namedMethod{"fetch"}))
+
+	res, err = router.Invoke("profile.get", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "fetch", res)
+}
+
+func TestRouter_RegisterAliasDuplicates(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterMethods(&namedMethod{"user.fetch"}, &namedMethod{"user.get"}))
+
+	err := router.RegisterAlias("user.get", "user.fetch") // must not shadow the registered method
+	assert.True(t, errors.Is(err, ErrMethodAlreadyRegistered))
+
+	res, rpcErr := router.Invoke("user.get", nil)
+	assert.Nil(t, rpcErr)
+	assert.Equal(t, "user.get", res)
+
+	assert.NoError(t, router.RegisterAlias("profile.get", "user.fetch"))
+	assert.NoError(t, router.RegisterAlias("profile.get", "user.get")) // overwriting is allowed by default
+
+	err = router.RegisterMethod(&namedMethod{"profile.get"})
+	assert.True(t, errors.Is(err, ErrMethodAlreadyRegistered))
+
+	router.DisallowDuplicates = true
+
+	err = router.RegisterAlias("profile.get", "user.fetch")
+	assert.True(t, errors.Is(err, ErrMethodAlreadyRegistered))
+
+	res, rpcErr = router.Invoke("profile.get", nil)
+	assert.Nil(t, rpcErr)
+	assert.Equal(t, "user.get", res)
+}
+
+func TestRouter_RegisterDeprecatedAlias(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		mutex  sync.Mutex
+		calls  = make([][2]string, 0)
+	)
+
+	router.DeprecatedCallHandler = func(_ context.Context, alias, methodName string) {
+		mutex.Lock()
+		calls = append(calls, [2]string{alias, methodName})
+		mutex.Unlock()
+	}
+
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"new"}))
+	assert.NoError(t, router.RegisterAlias("current", "new"))
+	assert.NoError(t, router.RegisterDeprecatedAlias("old", "new"))
+
+	for _, name := range []string{"new", "current", "old", "old"} {
+		res, err := router.Invoke(name, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "new", res)
+	}
+
+	assert.Equal(t, [][2]string{{"old", "new"}, {"old", "new"}}, calls)
+}
+
+func TestRouter_RegisterVersionedMethod(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		v1, v2 = &namedMethod{"user.get"}, &namedMethod{"user.get"}
+	)
+
+	assert.Error(t, router.RegisterVersionedMethod("", v1))
+	assert.Error(t, router.RegisterVersionedMethod("v1", &unnamedMethod{}))
+
+	assert.NoError(t, router.RegisterVersionedMethod("v1", v1))
+	assert.NoError(t, router.RegisterVersionedMethod("v2", v2))
+
+	m, ok := router.LookupMethod("user.get@v1")
+	assert.True(t, ok)
+	assert.Same(t, v1, m)
+
+	m, _ = router.LookupMethod("user.get@v2")
+	assert.Same(t, v2, m)
+
+	// without default version and unversioned method
+	_, ok = router.LookupMethod("user.get")
+	assert.False(t, ok)
+
+	router.DefaultVersion = "v1"
+
+	m, _ = router.LookupMethod("user.get")
+	assert.Same(t, v1, m)
+
+	// version from the context takes precedence
+	_, _, ok = router.lookup(WithVersion(context.Background(), "v3"), "user.get")
+	assert.False(t, ok)
+
+	_, err := router.InvokeContext(WithVersion(context.Background(), "v2"), "user.get", nil)
+	assert.Nil(t, err)
+
+	// unversioned method is used when version was not found
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"user.get"}))
+
+	res, err := router.InvokeContext(WithVersion(context.Background(), "v3"), "user.get", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "user.get", res)
+
+	router.DisallowDuplicates = true
+
+	assert.True(t, errors.Is(router.RegisterVersionedMethod("v1", v1), ErrMethodAlreadyRegistered))
+}
+
+func TestRouter_AliasWithVersion(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterVersionedMethod("v2", &namedMethod{"user.fetch"}))
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"user.fetch"}))
+	assert.NoError(t, router.RegisterAlias("user.get", "user.fetch"))
+	assert.NoError(t, router.RegisterAlias("user.get@v1", "user.fetch"))
+
+	res, err := router.InvokeContext(WithVersion(context.Background(), "v2"), "user.get", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "user.fetch", res)
+
+	m, ok := router.LookupMethod("user.get@v1")
+	assert.True(t, ok)
+	assert.Equal(t, "user.fetch", m.GetName())
+
+	_, err = router.Invoke("user.get@v3", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+}
+
+func TestVersionFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := VersionFromContext(context.Background())
+	assert.False(t, ok)
+
+	version, ok := VersionFromContext(WithVersion(context.Background(), "v1"))
+	assert.True(t, ok)
+	assert.Equal(t, "v1", version)
+}
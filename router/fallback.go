@@ -0,0 +1,194 @@
+package router
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+// Method name patterns wildcards (the same meaning is used for the authorization rules, see package authz).
+const (
+	// Wildcard matches exactly one method name segment in patterns (e.g.: `entity.*.get`).
+	Wildcard = "*"
+
+	// MultiWildcard matches one or more trailing method name segments, it is allowed as the last pattern segment
+	// only (e.g.: `entity.**`).
+	MultiWildcard = "**"
+)
+
+// Pattern binds method name pattern with a handler.
+type Pattern struct {
+	Pattern string
+	Handler Handler
+}
+
+type wildcardsCtxKey struct{}
+
+// MatchPattern checks method name against a pattern (segments are separated by Separator, Wildcard matches exactly
+// one segment, and MultiWildcard at the end of the pattern - one or more segments). Values of the matched wildcards
+// will be returned (MultiWildcard value contains all matched segments, e.g. `user.get` for `entity.**`).
+func MatchPattern(pattern, methodName string) ([]string, bool) {
+	var (
+		patternParts = strings.Split(pattern, Separator)
+		nameParts    = strings.Split(methodName, Separator)
+		last         = len(patternParts) - 1
+	)
+
+	if patternParts[last] == MultiWildcard && len(nameParts) > len(patternParts) {
+		nameParts = append(nameParts[:last], strings.Join(nameParts[last:], Separator))
+	}
+
+	if len(patternParts) != len(nameParts) {
+		return nil, false
+	}
+
+	wildcards := make([]string, 0)
+
+	for i, part := range patternParts {
+		switch {
+		case part == MultiWildcard && i == last && !hasEmptySegment(nameParts[i]):
+			wildcards = append(wildcards, nameParts[i])
+		case part == Wildcard && nameParts[i] != "":
+			wildcards = append(wildcards, nameParts[i])
+		case part != nameParts[i]:
+			return nil, false
+		}
+	}
+
+	return wildcards, true
+}
+
+// hasEmptySegment checks if the method name contains an empty segment.
+func hasEmptySegment(name string) bool {
+	for _, segment := range strings.Split(name, Separator) {
+		if segment == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PatternFallback creates fallback handler, that invokes handler of the first matched pattern. Matched wildcards
+// values are available for the handler using WildcardsFromContext function. When nothing was matched - `next`
+// handler will be invoked (if it is nil - "method not found" error will be returned).
+func PatternFallback(next Handler, patterns ...Pattern) Handler {
+	return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		for _, p := range patterns {
+			if wildcards, ok := MatchPattern(p.Pattern, methodName); ok {
+				return p.Handler(context.WithValue(ctx, wildcardsCtxKey{}, wildcards), methodName, params)
+			}
+		}
+
+		if next != nil {
+			return next(ctx, methodName, params)
+		}
+
+		return nil, rpcErrors.New(rpcErrors.MethodNotFound)
+	}
+}
+
+// WildcardsFromContext returns wildcards values, matched by PatternFallback.
+func WildcardsFromContext(ctx context.Context) []string {
+	wildcards, _ := ctx.Value(wildcardsCtxKey{}).([]string)
+
+	return wildcards
+}
+
+// SuggestionsFallback creates fallback handler, that returns "method not found" error with similar registered
+// method names in the error data (`{"suggestions": ["user.get"]}`). Suggestions count is limited by `limit`.
+func SuggestionsFallback(router *Router, limit int) Handler {
+	return func(_ context.Context, methodName string, _ interface{}) (interface{}, jsonrpc.Error) {
+		err := rpcErrors.New(rpcErrors.MethodNotFound)
+
+		if suggestions := Suggest(methodName, router.MethodNames(), limit); len(suggestions) > 0 {
+			err.Data = map[string]interface{}{"suggestions": suggestions}
+		}
+
+		return nil, err
+	}
+}
+
+// Suggest returns names, similar to the passed one (sorted by similarity).
+func Suggest(name string, names []string, limit int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	var (
+		maxDistance = len(name) / 4 //nolint:gomnd
+		candidates  = make([]candidate, 0)
+	)
+
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	for _, n := range names {
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(n)); d <= maxDistance {
+			candidates = append(candidates, candidate{name: n, distance: d})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance == candidates[j].distance {
+			return candidates[i].name < candidates[j].name
+		}
+
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	result := make([]string, 0, len(candidates))
+
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		result = append(result, candidates[i].name)
+	}
+
+	return result
+}
+
+// levenshtein calculates edit distance between two strings.
+func levenshtein(a, b string) int {
+	var (
+		ra, rb = []rune(a), []rune(b)
+		prev   = make([]int, len(rb)+1)
+		curr   = make([]int, len(rb)+1)
+	)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+
+	return result
+}
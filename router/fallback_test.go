@@ -0,0 +1,142 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+func TestRouter_Fallback(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	router.Fallback = func(_ context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+		return []interface{}{methodName, params}, nil
+	}
+
+	res, err := router.Invoke("legacy.method", []interface{}{1})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"legacy.method", []interface{}{1}}, res)
+
+	// sub-router without own fallback
+	_, err = router.Group("group").Invoke("unknown", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+
+	res, err = router.Invoke("group.unknown", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"group.unknown", nil}, res)
+
+	// sub-router with own fallback
+	router.Group("group").Fallback = func(_ context.Context, name string, _ interface{}) (interface{}, jsonrpc.Error) {
+		return name, nil
+	}
+
+	res, err = router.Invoke("group.unknown", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "unknown", res)
+}
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern, name string
+		wantOk        bool
+		wantWildcards []string
+	}{
+		{pattern: "entity.*.get", name: "entity.user.get", wantOk: true, wantWildcards: []string{"user"}},
+		{pattern: "*.*", name: "foo.bar", wantOk: true, wantWildcards: []string{"foo", "bar"}},
+		{pattern: "foo.bar", name: "foo.bar", wantOk: true, wantWildcards: []string{}},
+		{pattern: "entity.*.get", name: "entity..get"},
+		{pattern: "entity.*.get", name: "entity.user.set"},
+		{pattern: "entity.*.get", name: "entity.user.foo.get"},
+		{pattern: "entity.*", name: "entity"},
+		{pattern: "entity.*", name: "entity.user.get"},
+		{pattern: "entity.**", name: "entity.user", wantOk: true, wantWildcards: []string{"user"}},
+		{pattern: "entity.**", name: "entity.user.get", wantOk: true, wantWildcards: []string{"user.get"}},
+		{pattern: "*.**", name: "a.b.c", wantOk: true, wantWildcards: []string{"a", "b.c"}},
+		{pattern: "**", name: "a.b", wantOk: true, wantWildcards: []string{"a.b"}},
+		{pattern: "entity.**", name: "entity"},
+		{pattern: "entity.**", name: "entity.user..get"},
+		{pattern: "entity.**.get", name: "entity.user.get"},
+	}
+
+	for _, tt := range cases {
+		wildcards, ok := MatchPattern(tt.pattern, tt.name)
+
+		assert.Equal(t, tt.wantOk, ok, tt.name)
+		assert.Equal(t, tt.wantWildcards, wildcards, tt.name)
+	}
+}
+
+func TestPatternFallback(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	router.Fallback = PatternFallback(
+		SuggestionsFallback(router, 3),
+		Pattern{
+			Pattern: "entity.*.get",
+			Handler: func(ctx context.Context, _ string, _ interface{}) (interface{}, jsonrpc.Error) {
+				return WildcardsFromContext(ctx), nil
+			},
+		},
+	)
+
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"entity.list"}))
+
+	res, err := router.Invoke("entity.order.get", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"order"}, res)
+
+	_, err = router.Invoke("entity.lsit", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+	assert.Equal(t, map[string]interface{}{"suggestions": []string{"entity.list"}}, err.GetData())
+
+	_, err = PatternFallback(nil)(context.Background(), "foo", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+}
+
+func TestSuggestionsFallback(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	router.Fallback = SuggestionsFallback(router, 2)
+
+	assert.NoError(t, router.RegisterMethods(&namedMethod{"get"}, &namedMethod{"set"}, &namedMethod{"delete"}))
+	assert.NoError(t, router.Group("user").RegisterMethods(&namedMethod{"get"}, &namedMethod{"list"}))
+
+	_, err := router.Invoke("user.gte", nil)
+	assert.Equal(t, map[string]interface{}{"suggestions": []string{"user.get"}}, err.GetData())
+
+	_, err = router.Invoke("sett", nil)
+	assert.Equal(t, map[string]interface{}{"suggestions": []string{"set"}}, err.GetData())
+
+	_, err = router.Invoke("something.completely.different", nil)
+	assert.Nil(t, err.GetData())
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"user.get", "user.set", "user.list", "order.get"}
+
+	assert.Equal(t, []string{"user.get", "user.set"}, Suggest("User.Get", names, 5))
+	assert.Equal(t, []string{"user.get"}, Suggest("user.Get", names, 1))
+	assert.Equal(t, []string{"user.get", "user.set"}, Suggest("user.xet", names, 5))
+	assert.Empty(t, Suggest("foo", names, 5))
+}
+
+func TestRouter_MethodNames(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterMethods(&namedMethod{"b"}, &namedMethod{"a"}))
+	assert.NoError(t, router.Group("x").Group("y").RegisterMethod(&namedMethod{"c"}))
+
+	assert.Equal(t, []string{"a", "b", "x.y.c"}, router.MethodNames())
+}
@@ -0,0 +1,89 @@
+package router
+
+import (
+	"errors"
+	"strings"
+)
+
+// Separator is used for method names namespacing (e.g.: `billing.invoice.create`).
+const Separator = "."
+
+// Mount attaches sub-router to the current router using passed prefix. All methods of the sub-router will be
+// available with a prefix (`prefix.method`), and sub-router middlewares will be executed only for its methods.
+// Methods, registered in the current router, take precedence over the mounted methods with the same names.
+func (router *Router) Mount(prefix string, child *Router) error {
+	prefix = strings.Trim(prefix, Separator)
+
+	if prefix == "" {
+		return errors.New("jsonrpc: mounting prefix should not be empty")
+	}
+
+	if child == nil {
+		return errors.New("jsonrpc: mounted router should not be nil")
+	}
+
+	if child == router || child.contains(router) {
+		return errors.New("jsonrpc: router cannot be mounted into itself")
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if _, exists := router.mounts[prefix]; exists {
+		return errors.New("jsonrpc: prefix " + prefix + " is already mounted")
+	}
+
+	router.mounts[prefix] = child
+
+	return nil
+}
+
+// Group returns sub-router for passed prefix. Sub-router will be created and mounted, if it was not done before.
+// Group panics if prefix is empty.
+func (router *Router) Group(prefix string) *Router {
+	prefix = strings.Trim(prefix, Separator)
+
+	if prefix == "" {
+		panic("jsonrpc: group prefix should not be empty")
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if child, exists := router.mounts[prefix]; exists {
+		return child
+	}
+
+	child := New()
+	router.mounts[prefix] = child
+
+	return child
+}
+
+// findMount looks for a mounted sub-router with the longest prefix matched with method name. Method name without
+// prefix will be returned too. Must be called under lock.
+func (router *Router) findMount(methodName string) (child *Router, rest string) {
+	var longest int
+
+	for prefix, mounted := range router.mounts {
+		if len(prefix) > longest && strings.HasPrefix(methodName, prefix+Separator) {
+			child, rest, longest = mounted, methodName[len(prefix)+len(Separator):], len(prefix)
+		}
+	}
+
+	return
+}
+
+// contains checks if passed router is mounted into the current router (deeply).
+func (router *Router) contains(target *Router) bool {
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+
+	for _, mounted := range router.mounts {
+		if mounted == target || mounted.contains(target) {
+			return true
+		}
+	}
+
+	return false
+}
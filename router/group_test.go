@@ -0,0 +1,79 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+func TestRouter_Group(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	billing := router.Group("billing")
+
+	assert.Same(t, billing, router.Group(".billing."))
+	assert.NoError(t, billing.Group("invoice").RegisterMethod(&nothingMethod{}))
+
+	assert.True(t, router.MethodIsRegistered("billing.invoice.nothing"))
+	assert.True(t, billing.MethodIsRegistered("invoice.nothing"))
+	assert.False(t, router.MethodIsRegistered("nothing"))
+	assert.False(t, router.MethodIsRegistered("billing.nothing"))
+
+	res, err := router.Invoke("billing.invoice.nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res)
+
+	assert.Panics(t, func() { router.Group("") })
+}
+
+func TestRouter_Mount(t *testing.T) {
+	t.Parallel()
+
+	router, users, admins := New(), New(), New()
+
+	assert.NoError(t, users.RegisterMethod(&nothingMethod{}))
+	assert.NoError(t, admins.RegisterMethod(&erroredMethod{}))
+
+	assert.NoError(t, router.Mount("users", users))
+	assert.NoError(t, router.Mount("users.admins", admins))
+
+	assert.Contains(t, router.Mount("users", New()).Error(), "already mounted")
+	assert.Contains(t, router.Mount("", New()).Error(), "not be empty")
+	assert.Contains(t, router.Mount("foo", nil).Error(), "not be nil")
+	assert.Contains(t, router.Mount("foo", router).Error(), "into itself")
+	assert.Contains(t, admins.Mount("foo", router).Error(), "into itself")
+
+	res, err := router.Invoke("users.nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res)
+
+	// the longest prefix must be used
+	res, err = router.Invoke("users.admins.nothing", nil)
+	assert.Nil(t, res)
+	assert.Equal(t, 1, err.GetCode())
+
+	_, err = router.Invoke("users.unknown", nil)
+	assert.Equal(t, int(rpcErrors.MethodNotFound), err.GetCode())
+}
+
+func TestRouter_MountedMethodsNamesCollisions(t *testing.T) {
+	t.Parallel()
+
+	router, first, second := New(), New(), New()
+
+	// both modules use the same method name, but it does not collide after mounting
+	assert.NoError(t, first.RegisterMethod(&nothingMethod{}))
+	assert.NoError(t, second.RegisterMethod(&erroredMethod{}))
+
+	assert.NoError(t, router.Mount("first", first))
+	assert.NoError(t, router.Mount("second", second))
+
+	res, err := router.Invoke("first.nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res)
+
+	_, err = router.Invoke("second.nothing", nil)
+	assert.Equal(t, 1, err.GetCode())
+}
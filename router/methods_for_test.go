@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"errors"
 
 	"github.com/tarampampam/go-jsonrpc"
@@ -46,3 +47,23 @@ func (*withParamsValidationMethod) GetName() string            { return "validat
 func (*withParamsValidationMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
 	return true, nil
 }
+
+type namedMethod struct{ name string }
+
+func (*namedMethod) GetParamsType() interface{}                          { return nil }
+func (m *namedMethod) GetName() string                                   { return m.name }
+func (m *namedMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) { return m.name, nil }
+
+type (
+	contextMethod    struct{}
+	contextMethodKey struct{}
+)
+
+func (*contextMethod) GetParamsType() interface{} { return nil }
+func (*contextMethod) GetName() string            { return "context" }
+func (*contextMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return "without context", nil
+}
+func (*contextMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	return ctx.Value(contextMethodKey{}), nil
+}
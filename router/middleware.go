@@ -0,0 +1,64 @@
+package router
+
+import (
+	"context"
+
+	"github.com/tarampampam/go-jsonrpc"
+)
+
+type (
+	// Handler invokes method with passed name and params.
+	Handler func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error)
+
+	// Middleware wraps the Handler and allows to run some code before and after method invoking.
+	Middleware func(next Handler) Handler
+)
+
+type (
+	methodCtxKey     struct{}
+	methodNameCtxKey struct{}
+)
+
+// Use appends middlewares into the router middlewares stack. Middlewares are executed in the order of
+// registration, and middlewares of mounted sub-router are executed after the parent router middlewares.
+func (router *Router) Use(middlewares ...Middleware) {
+	router.mutex.Lock()
+	router.middlewares = append(router.middlewares, middlewares...)
+	router.mutex.Unlock()
+}
+
+// handler builds a handler with all registered middlewares.
+func (router *Router) handler() Handler {
+	router.mutex.RLock()
+	var middlewares = router.middlewares
+	router.mutex.RUnlock()
+
+	var handler Handler = router.dispatch
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// MethodFromContext returns a method that is going to be invoked (it is available for middlewares). If the method
+// was not found - `false` will be returned.
+func MethodFromContext(ctx context.Context) (jsonrpc.Method, bool) {
+	method, ok := ctx.Value(methodCtxKey{}).(jsonrpc.Method)
+
+	return method, ok
+}
+
+// MethodNameFromContext returns the full registered name of the method, that is going to be invoked (see
+// Router.CanonicalName). It should be used instead of the requested method name for the access rules, limits, caching
+// and so on, because the method can be called using the alias or without version.
+func MethodNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(methodNameCtxKey{}).(string)
+
+	return name, ok
+}
+
+func withMethod(ctx context.Context, method jsonrpc.Method, name string) context.Context {
+	return context.WithValue(context.WithValue(ctx, methodCtxKey{}, method), methodNameCtxKey{}, name)
+}
@@ -0,0 +1,148 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+type testCtxKey struct{}
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			*calls = append(*calls, name+":"+methodName)
+
+			return next(ctx, methodName, params)
+		}
+	}
+}
+
+func TestRouter_Use(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		group  = router.Group("group")
+		calls  = make([]string, 0)
+	)
+
+	router.Use(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls))
+	group.Use(recordingMiddleware("group", &calls))
+
+	assert.NoError(t, router.RegisterMethod(&nothingMethod{}))
+	assert.NoError(t, group.RegisterMethod(&nothingMethod{}))
+
+	_, err := router.Invoke("nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first:nothing", "second:nothing"}, calls)
+
+	calls = calls[:0]
+
+	_, err = router.Invoke("group.nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first:group.nothing", "second:group.nothing", "group:nothing"}, calls)
+}
+
+func TestRouter_MiddlewareCanBreakTheChain(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	router.Use(func(next Handler) Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			if ctx.Value(testCtxKey{}) == nil {
+				return nil, rpcErrors.New(rpcErrors.Internal)
+			}
+
+			return next(ctx, methodName, params)
+		}
+	})
+
+	assert.NoError(t, router.RegisterMethod(&nothingMethod{}))
+
+	_, err := router.Invoke("nothing", nil)
+	assert.Equal(t, int(rpcErrors.Internal), err.GetCode())
+
+	res, err := router.InvokeContext(context.WithValue(context.Background(), testCtxKey{}, true), "nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res)
+}
+
+func TestMethodFromContext(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		method = &nothingMethod{}
+		found  []jsonrpc.Method
+	)
+
+	router.Use(func(next Handler) Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			m, _ := MethodFromContext(ctx)
+			found = append(found, m)
+
+			return next(ctx, methodName, params)
+		}
+	})
+
+	assert.NoError(t, router.Group("foo").RegisterMethod(method))
+
+	_, _ = router.Invoke("foo.nothing", nil)
+	_, _ = router.Invoke("unknown", nil)
+
+	assert.Equal(t, []jsonrpc.Method{method, nil}, found)
+}
+
+func TestMethodNameFromContext(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		group  = router.Group("users")
+		found  []string
+	)
+
+	record := func(next Handler) Handler {
+		return func(ctx context.Context, methodName string, params interface{}) (interface{}, jsonrpc.Error) {
+			name, _ := MethodNameFromContext(ctx)
+			found = append(found, name)
+
+			return next(ctx, methodName, params)
+		}
+	}
+
+	router.Use(record)
+	group.Use(record)
+
+	assert.NoError(t, group.RegisterMethod(&namedMethod{"fetch"}))
+	assert.NoError(t, group.RegisterVersionedMethod("v2", &namedMethod{"fetch"}))
+	assert.NoError(t, router.RegisterAlias("user.get", "users.fetch"))
+
+	_, _ = router.Invoke("users.fetch", nil)
+	_, _ = router.Invoke("user.get", nil)
+	_, _ = router.Invoke("users.fetch@v2", nil)
+	_, _ = router.Invoke("unknown", nil)
+
+	assert.Equal(t, []string{
+		"users.fetch", "users.fetch",
+		"users.fetch", "users.fetch",
+		"users.fetch@v2", "users.fetch@v2",
+		"",
+	}, found)
+
+	name, ok := router.CanonicalName("user.get")
+	assert.True(t, ok)
+	assert.Equal(t, "users.fetch", name)
+
+	_, ok = router.CanonicalName("unknown")
+	assert.False(t, ok)
+
+	name, ok = router.CanonicalNameContext(WithVersion(context.Background(), "v2"), "user.get")
+	assert.True(t, ok)
+	assert.Equal(t, "users.fetch@v2", name)
+}
@@ -1,7 +1,11 @@
 package router
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	jsoniter "github.com/json-iterator/go"
@@ -9,11 +13,39 @@ import (
 	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
 )
 
+var (
+	// ErrMethodAlreadyRegistered is returned when method with the same name is already registered (and overwriting
+	// is disallowed).
+	ErrMethodAlreadyRegistered = errors.New("jsonrpc: method is already registered") //nolint:gochecknoglobals
+
+	// ErrMethodNotRegistered is returned when method with passed name is not registered.
+	ErrMethodNotRegistered = errors.New("jsonrpc: method is not registered") //nolint:gochecknoglobals
+
+	errEmptyMethodName = errors.New("jsonrpc: method name should not be empty") //nolint:gochecknoglobals
+)
+
 // Router is default RPC router implementation.
 type Router struct {
-	mutex   sync.RWMutex
-	methods map[string]jsonrpc.Method
-	json    jsoniter.API
+	// DisallowDuplicates makes RegisterMethod to return an error instead of existing method overwriting.
+	DisallowDuplicates bool
+
+	// Fallback (optional) will be invoked for unknown methods instead of "method not found" error returning. Mounted
+	// sub-routers use their own fallback handlers (when they are defined) for the methods with their prefixes.
+	Fallback Handler
+
+	// DefaultVersion is used for the versioned methods resolving, when method was called without version and
+	// version is not defined in the context.
+	DefaultVersion string
+
+	// DeprecatedCallHandler (optional) will be called on each deprecated alias invoking.
+	DeprecatedCallHandler func(ctx context.Context, alias, methodName string)
+
+	mutex       sync.RWMutex
+	methods     map[string]jsonrpc.Method
+	mounts      map[string]*Router // key is a prefix (without separator)
+	aliases     map[string]alias
+	middlewares []Middleware
+	json        jsoniter.API
 }
 
 // New creates new router instance.
@@ -21,45 +53,314 @@ func New() *Router {
 	return &Router{
 		mutex:   sync.RWMutex{},
 		methods: map[string]jsonrpc.Method{},
+		mounts:  map[string]*Router{},
+		aliases: map[string]alias{},
 		json:    jsoniter.ConfigFastest,
 	}
 }
 
-// RegisterMethod make a method registration for later invoking.
+// RegisterMethod make a method registration for later invoking. Existing method with the same name will be
+// overwritten, if DisallowDuplicates is not set.
 func (router *Router) RegisterMethod(method jsonrpc.Method) error {
+	return router.RegisterMethods(method)
+}
+
+// RegisterMethods makes an atomic registration for the set of methods - if any method cannot be registered, none of
+// them will be registered.
+func (router *Router) RegisterMethods(methods ...jsonrpc.Method) error {
+	set, err := methodsSet(methods)
+	if err != nil {
+		return err
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	for name := range set {
+		if _, exists := router.methods[name]; exists && router.DisallowDuplicates {
+			return fmt.Errorf("%w: %s", ErrMethodAlreadyRegistered, name)
+		}
+
+		if _, exists := router.aliases[name]; exists {
+			return fmt.Errorf("%w: %s (alias)", ErrMethodAlreadyRegistered, name)
+		}
+	}
+
+	for name, method := range set {
+		router.methods[name] = method
+	}
+
+	return nil
+}
+
+// ReplaceMethod replaces already registered method with the same name (regardless of DisallowDuplicates).
+func (router *Router) ReplaceMethod(method jsonrpc.Method) error {
 	var methodName = method.GetName()
 
 	if methodName == "" {
-		return errors.New("jsonrpc: method name should not be empty")
+		return errEmptyMethodName
 	}
 
 	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if _, exists := router.methods[methodName]; !exists {
+		return fmt.Errorf("%w: %s", ErrMethodNotRegistered, methodName)
+	}
+
 	router.methods[methodName] = method
-	router.mutex.Unlock()
 
 	return nil
 }
 
-// MethodIsRegistered returns `true` only if passed method is registered.
+// UnregisterMethod removes registered method.
+func (router *Router) UnregisterMethod(methodName string) error {
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if _, exists := router.methods[methodName]; !exists {
+		return fmt.Errorf("%w: %s", ErrMethodNotRegistered, methodName)
+	}
+
+	delete(router.methods, methodName)
+
+	return nil
+}
+
+// SwapMethods atomically replaces all methods, registered in the current router (mounted sub-routers are not
+// affected), with the passed set. It allows to reload a module (e.g. router group) methods without a window where
+// they are missing. Methods, that are shadowed by the registered aliases, are rejected (as by RegisterMethods).
+func (router *Router) SwapMethods(methods ...jsonrpc.Method) error {
+	set, err := methodsSet(methods)
+	if err != nil {
+		return err
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	for name := range set {
+		if _, exists := router.aliases[name]; exists {
+			return fmt.Errorf("%w: %s (alias)", ErrMethodAlreadyRegistered, name)
+		}
+	}
+
+	router.methods = set
+
+	return nil
+}
+
+// methodsSet converts methods slice into the map (with names checking).
+func methodsSet(methods []jsonrpc.Method) (map[string]jsonrpc.Method, error) {
+	set := make(map[string]jsonrpc.Method, len(methods))
+
+	for _, method := range methods {
+		var methodName = method.GetName()
+
+		if methodName == "" {
+			return nil, errEmptyMethodName
+		}
+
+		if _, exists := set[methodName]; exists {
+			return nil, fmt.Errorf("%w: %s (duplicated in the set)", ErrMethodAlreadyRegistered, methodName)
+		}
+
+		set[methodName] = method
+	}
+
+	return set, nil
+}
+
+// MethodIsRegistered returns `true` only if passed method is registered (in current router or in any mounted
+// sub-router).
 func (router *Router) MethodIsRegistered(methodName string) bool {
+	_, ok := router.LookupMethod(methodName)
+
+	return ok
+}
+
+// LookupMethod returns registered method by its full name (mounted sub-routers, aliases and default version are
+// considered too).
+func (router *Router) LookupMethod(methodName string) (jsonrpc.Method, bool) {
+	method, _, ok := router.lookup(context.Background(), methodName)
+
+	return method, ok
+}
+
+// CanonicalName returns the full registered name of the method, that will be invoked for passed name (aliases,
+// versions and mounted sub-routers are resolved, e.g. `user.get` can be resolved into `users.fetch@v2`).
+func (router *Router) CanonicalName(methodName string) (string, bool) {
+	return router.CanonicalNameContext(context.Background(), methodName)
+}
+
+// CanonicalNameContext is like CanonicalName, but the method version, chosen using the context (see WithVersion), is
+// resolved too.
+func (router *Router) CanonicalNameContext(ctx context.Context, methodName string) (string, bool) {
+	_, name, ok := router.lookup(ctx, methodName)
+
+	return name, ok
+}
+
+// lookup returns the method and its full registered name.
+func (router *Router) lookup(ctx context.Context, methodName string) (jsonrpc.Method, string, bool) {
+	r := router.resolve(ctx, methodName)
+
+	if r.method != nil {
+		return r.method, r.name, true
+	}
+
+	if r.child != nil {
+		if method, name, ok := r.child.lookup(ctx, r.rest); ok {
+			return method, r.prefix + Separator + name, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// route is a result of the method name resolving.
+type route struct {
+	method     jsonrpc.Method // method, registered in the current router
+	name       string         // registered method name (with version)
+	child      *Router        // or mounted sub-router
+	prefix     string         // mounted sub-router prefix
+	rest       string         // method name for the sub-router (without prefix)
+	alias      string         // used alias name (empty, when method was called without alias)
+	target     string         // method name, that alias points to
+	deprecated bool           // used alias is deprecated
+}
+
+// resolve looks for the method (or mounted sub-router) using method name, aliases and versions.
+func (router *Router) resolve(ctx context.Context, methodName string) (r route) {
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+
+	if a, ok := router.aliases[methodName]; ok {
+		r.alias, r.target, r.deprecated = methodName, a.target, a.deprecated
+		methodName = a.target
+	}
+
+	if !strings.Contains(methodName, VersionSeparator) {
+		version, ok := VersionFromContext(ctx)
+		if !ok {
+			version = router.DefaultVersion
+		}
+
+		if version != "" {
+			if method, ok := router.methods[methodName+VersionSeparator+version]; ok {
+				r.method, r.name = method, methodName+VersionSeparator+version
+
+				return
+			}
+		}
+	}
+
+	if method, ok := router.methods[methodName]; ok {
+		r.method, r.name = method, methodName
+
+		return
+	}
+
+	r.child, r.rest = router.findMount(methodName)
+
+	if r.child != nil {
+		r.prefix = methodName[:len(methodName)-len(r.rest)-len(Separator)]
+	}
+
+	return
+}
+
+// MethodNames returns sorted full names of all registered methods (including methods of mounted sub-routers).
+func (router *Router) MethodNames() []string {
 	router.mutex.RLock()
-	_, ok := router.methods[methodName]
+	names := make([]string, 0, len(router.methods))
+
+	for name := range router.methods {
+		names = append(names, name)
+	}
+
+	mounts := make(map[string]*Router, len(router.mounts))
+	for prefix, child := range router.mounts {
+		mounts[prefix] = child
+	}
 	router.mutex.RUnlock()
 
-	return ok
+	for prefix, child := range mounts {
+		for _, name := range child.MethodNames() {
+			names = append(names, prefix+Separator+name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 // Invoke accepts method name and invoke registered method with same name. If requested method is not
 // registered - error will be returned.
 func (router *Router) Invoke(methodName string, params interface{}) (interface{}, jsonrpc.Error) {
-	router.mutex.RLock()
-	method, ok := router.methods[methodName]
-	router.mutex.RUnlock()
+	return router.InvokeContext(context.Background(), methodName, params)
+}
+
+// InvokeContext works like Invoke, but passes the context through the middlewares chain.
+func (router *Router) InvokeContext(
+	ctx context.Context,
+	methodName string,
+	params interface{},
+) (interface{}, jsonrpc.Error) {
+	if method, name, ok := router.lookup(ctx, methodName); ok {
+		ctx = withMethod(ctx, method, name)
+	}
+
+	return router.handler()(ctx, methodName, params)
+}
 
-	if !ok {
-		return nil, rpcErrors.New(rpcErrors.MethodNotFound)
+// dispatch invokes method, registered in current router, or passes invoking into mounted sub-router.
+func (router *Router) dispatch(
+	ctx context.Context,
+	methodName string,
+	params interface{},
+) (interface{}, jsonrpc.Error) {
+	r := router.resolve(ctx, methodName)
+
+	if r.deprecated && router.DeprecatedCallHandler != nil {
+		router.DeprecatedCallHandler(ctx, r.alias, r.target)
+	}
+
+	if r.method != nil {
+		return router.call(ctx, r.method, params)
+	}
+
+	// sub-router without matched method and fallback passes invoking back to the current router fallback
+	if r.child != nil && (router.Fallback == nil || r.child.handles(ctx, r.rest)) {
+		// method was resolved (and attached to the context) by the root router, so its full name is kept
+		return r.child.handler()(ctx, r.rest, params)
+	}
+
+	if router.Fallback != nil {
+		return router.Fallback(ctx, methodName, params)
+	}
+
+	return nil, rpcErrors.New(rpcErrors.MethodNotFound)
+}
+
+// handles checks if router has a registered method or fallback for passed method name.
+func (router *Router) handles(ctx context.Context, methodName string) bool {
+	if router.Fallback != nil {
+		return true
 	}
 
+	r := router.resolve(ctx, methodName)
+
+	return r.method != nil || (r.child != nil && r.child.handles(ctx, r.rest))
+}
+
+// call binds params into method params type (when it is defined) and calls method handler.
+func (router *Router) call(
+	ctx context.Context,
+	method jsonrpc.Method,
+	params interface{},
+) (interface{}, jsonrpc.Error) {
 	// this is crutch for request params binding into required structure
 	methodParams := method.GetParamsType()
 	if methodParams != nil {
@@ -80,5 +381,9 @@ func (router *Router) Invoke(methodName string, params interface{}) (interface{}
 		}
 	}
 
+	if m, ok := method.(jsonrpc.ContextMethod); ok {
+		return m.HandleContext(ctx, methodParams)
+	}
+
 	return method.Handle(methodParams)
 }
@@ -1,6 +1,9 @@
 package router
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,6 +36,24 @@ func TestRouter_Invoke(t *testing.T) {
 	assert.Equal(t, 1, res)
 }
 
+func TestRouter_InvokeContextMethod(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterMethod(&contextMethod{}))
+
+	res, err := router.InvokeContext(context.WithValue(context.Background(), contextMethodKey{}, "foo"), "context", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", res)
+
+	res, err = router.Invoke("context", nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+}
+
 func TestRouter_InvokeUnknownMethod(t *testing.T) {
 	t.Parallel()
 
@@ -89,3 +110,132 @@ func TestRouter_InvokeWithParamsValidationAndJsonMarshaling(t *testing.T) {
 	assert.Nil(t, res)
 	assert.Equal(t, "foo", err.GetData())
 }
+
+func TestRouter_RegisterMethodOverwriting(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.NoError(t, router.RegisterMethod(&erroredMethod{}))
+	assert.NoError(t, router.RegisterMethod(&nothingMethod{})) // same name
+
+	res, err := router.Invoke("nothing", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res)
+
+	router.DisallowDuplicates = true
+
+	regErr := router.RegisterMethod(&erroredMethod{})
+	assert.True(t, errors.Is(regErr, ErrMethodAlreadyRegistered))
+	assert.Contains(t, regErr.Error(), "nothing")
+
+	res, _ = router.Invoke("nothing", nil)
+	assert.Equal(t, 1, res)
+}
+
+func TestRouter_ReplaceMethod(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	router.DisallowDuplicates = true
+
+	assert.True(t, errors.Is(router.ReplaceMethod(&nothingMethod{}), ErrMethodNotRegistered))
+	assert.Contains(t, router.ReplaceMethod(&unnamedMethod{}).Error(), "not be empty")
+
+	assert.NoError(t, router.RegisterMethod(&nothingMethod{}))
+	assert.NoError(t, router.ReplaceMethod(&erroredMethod{}))
+
+	_, err := router.Invoke("nothing", nil)
+	assert.Equal(t, 1, err.GetCode())
+}
+
+func TestRouter_UnregisterMethod(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+
+	assert.True(t, errors.Is(router.UnregisterMethod("nothing"), ErrMethodNotRegistered))
+
+	assert.NoError(t, router.RegisterMethod(&nothingMethod{}))
+	assert.NoError(t, router.UnregisterMethod("nothing"))
+
+	assert.False(t, router.MethodIsRegistered("nothing"))
+}
+
+func TestRouter_RegisterMethods(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	router.DisallowDuplicates = true
+
+	assert.NoError(t, router.RegisterMethods(&namedMethod{"foo"}, &namedMethod{"bar"}))
+	assert.True(t, router.MethodIsRegistered("foo"))
+	assert.True(t, router.MethodIsRegistered("bar"))
+
+	// nothing must be registered, when any method in the set cannot be registered
+	assert.True(t, errors.Is(router.RegisterMethods(&namedMethod{"baz"}, &namedMethod{"foo"}), ErrMethodAlreadyRegistered))
+	assert.False(t, router.MethodIsRegistered("baz"))
+
+	assert.True(t, errors.Is(router.RegisterMethods(&namedMethod{"a"}, &namedMethod{"a"}), ErrMethodAlreadyRegistered))
+	assert.Error(t, router.RegisterMethods(&namedMethod{"b"}, &unnamedMethod{}))
+	assert.False(t, router.MethodIsRegistered("a"))
+	assert.False(t, router.MethodIsRegistered("b"))
+}
+
+func TestRouter_SwapMethods(t *testing.T) {
+	t.Parallel()
+
+	router := New()
+	module := router.Group("module")
+
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"root"}))
+	assert.NoError(t, module.RegisterMethods(&namedMethod{"foo"}, &namedMethod{"bar"}))
+
+	assert.Error(t, module.SwapMethods(&namedMethod{"baz"}, &unnamedMethod{}))
+	assert.True(t, router.MethodIsRegistered("module.foo"))
+
+	assert.NoError(t, module.SwapMethods(&namedMethod{"foo"}, &namedMethod{"baz"}))
+
+	assert.True(t, router.MethodIsRegistered("root"))
+	assert.True(t, router.MethodIsRegistered("module.foo"))
+	assert.True(t, router.MethodIsRegistered("module.baz"))
+	assert.False(t, router.MethodIsRegistered("module.bar"))
+
+	// methods, shadowed by the aliases, are rejected, and the methods are not swapped
+	assert.NoError(t, module.RegisterAlias("qux", "foo"))
+
+	err := module.SwapMethods(&namedMethod{"foo"}, &namedMethod{"qux"})
+	assert.True(t, errors.Is(err, ErrMethodAlreadyRegistered))
+	assert.True(t, router.MethodIsRegistered("module.baz"))
+}
+
+func TestRouter_ConcurrentSwapAndInvoke(t *testing.T) {
+	t.Parallel()
+
+	var (
+		router = New()
+		wg     sync.WaitGroup
+	)
+
+	assert.NoError(t, router.RegisterMethod(&namedMethod{"foo"}))
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, router.SwapMethods(&namedMethod{"foo"}, &namedMethod{"bar"}))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			res, err := router.Invoke("foo", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, "foo", res)
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,44 @@
+// Package session describes client sessions on persistent connections (WebSocket, TCP, stdio, etc.), that allow
+// server to push notifications to the client.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Session is a client session on the persistent connection.
+type Session interface {
+	// ID returns unique session identifier.
+	ID() string
+
+	// Notify sends a notification (request without ID) to the client.
+	Notify(method string, params interface{}) error
+
+	// Done returns a channel, that is closed when the session (connection) is closed.
+	Done() <-chan struct{}
+}
+
+type ctxKey struct{}
+
+// WithSession returns a context with attached session.
+func WithSession(ctx context.Context, s Session) context.Context {
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// FromContext returns session, attached to the context using WithSession.
+func FromContext(ctx context.Context) (Session, bool) {
+	s, ok := ctx.Value(ctxKey{}).(Session)
+
+	return s, ok && s != nil
+}
+
+// NewID generates random identifier (e.g. for the sessions or subscriptions).
+func NewID() string {
+	var b [16]byte
+
+	_, _ = rand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
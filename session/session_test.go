@@ -0,0 +1,35 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSession struct{}
+
+func (fakeSession) ID() string                       { return "foo" }
+func (fakeSession) Notify(string, interface{}) error { return nil }
+func (fakeSession) Done() <-chan struct{}            { return nil }
+
+func TestWithSessionAndFromContext(t *testing.T) {
+	t.Parallel()
+
+	s, ok := FromContext(context.Background())
+	assert.False(t, ok)
+	assert.Nil(t, s)
+
+	s, ok = FromContext(WithSession(context.Background(), fakeSession{}))
+	assert.True(t, ok)
+	assert.Equal(t, "foo", s.ID())
+}
+
+func TestNewID(t *testing.T) {
+	t.Parallel()
+
+	id := NewID()
+
+	assert.Regexp(t, `^[0-9a-f]{32}$`, id)
+	assert.NotEqual(t, id, NewID())
+}
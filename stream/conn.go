@@ -0,0 +1,173 @@
+// Package stream provides JsonRPC transport over the persistent stream connections (TCP, stdio, WebSocket adapters,
+// etc.). Connection implements session.Session, so server can push notifications to the client.
+package stream
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// ErrClosed is returned on writing into the closed connection.
+var ErrClosed = errors.New("jsonrpc: connection is closed")
+
+// Conn serves JsonRPC requests on the stream connection. Incoming messages are JSON values (requests or batches),
+// outgoing messages (responses and notifications) are newline-delimited JSON values.
+type Conn struct {
+	kernel *rpcKernel.Kernel
+	rwc    io.ReadWriteCloser
+	json   jsoniter.API
+	id     string
+
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+	done       chan struct{}
+}
+
+// NewConn creates new connection for the kernel.
+func NewConn(kernel *rpcKernel.Kernel, rwc io.ReadWriteCloser) *Conn {
+	return &Conn{
+		kernel: kernel,
+		rwc:    rwc,
+		json:   jsoniter.ConfigFastest,
+		id:     session.NewID(),
+		done:   make(chan struct{}),
+	}
+}
+
+// ID implements session.Session interface.
+func (conn *Conn) ID() string { return conn.id }
+
+// Done implements session.Session interface.
+func (conn *Conn) Done() <-chan struct{} { return conn.done }
+
+// Notify implements session.Session interface.
+func (conn *Conn) Notify(method string, params interface{}) error {
+	data, err := conn.json.Marshal(rpcRequest.Notification{Version: jsonrpc.Version, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	return conn.write(data)
+}
+
+// Serve reads and processes requests until the connection is closed (or the context is canceled). Requests are
+// processed concurrently, and the session is attached to the requests context (see session.FromContext). In-flight
+// requests are canceled, when reading stops, and the connection is closed on exit. Returned error is nil when the
+// client closed the connection.
+func (conn *Conn) Serve(ctx context.Context) error {
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close() // unblocks reading
+		case <-conn.done:
+		}
+	}()
+
+	reqCtx, cancel := context.WithCancel(session.WithSession(ctx, conn))
+
+	var (
+		wg      sync.WaitGroup
+		decoder = json.NewDecoder(conn.rwc) // std decoder reports syntax errors without waiting for more input
+	)
+
+	defer wg.Wait() // in-flight requests should be completed before the connection closing
+	defer cancel()  // client is gone (or reading is failed), so in-flight requests are canceled
+
+	for {
+		var message json.RawMessage
+
+		if err := decoder.Decode(&message); err != nil {
+			if errors.Is(err, io.EOF) || conn.closed() {
+				return nil
+			}
+
+			// stream cannot be synchronized after the syntax error, so the connection is closed
+			data, _ := conn.json.Marshal(rpcResponse.Response{Version: jsonrpc.Version, Error: rpcErrors.New(rpcErrors.Parse)})
+			_ = conn.write(data)
+
+			return err
+		}
+
+		wg.Add(1)
+
+		go func(message []byte) {
+			defer wg.Done()
+
+			ctx := metadata.NewContext(reqCtx, conn.metadata())
+
+			if response := conn.kernel.HandleJSONRequestContext(ctx, message); len(response) > 0 {
+				_ = conn.write(response)
+			}
+		}(message)
+	}
+}
+
+// Close closes the connection (and the session).
+func (conn *Conn) Close() (err error) {
+	conn.closeOnce.Do(func() {
+		conn.writeMutex.Lock()
+		defer conn.writeMutex.Unlock()
+
+		close(conn.done)
+
+		err = conn.rwc.Close()
+	})
+
+	return err
+}
+
+// metadata returns new message metadata. Remote address and TLS state are filled when the underlying connection
+// provides them (as net.Conn and tls.Conn do).
+func (conn *Conn) metadata() *metadata.Metadata {
+	md := &metadata.Metadata{Transport: metadata.TransportStream, SessionID: conn.id}
+
+	if addressed, ok := conn.rwc.(interface{ RemoteAddr() net.Addr }); ok && addressed.RemoteAddr() != nil {
+		md.RemoteAddr = addressed.RemoteAddr().String()
+	}
+
+	if secured, ok := conn.rwc.(interface{ ConnectionState() tls.ConnectionState }); ok {
+		state := secured.ConnectionState()
+		md.TLS = &state
+	}
+
+	return md
+}
+
+func (conn *Conn) closed() bool {
+	select {
+	case <-conn.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// write writes the message (with trailing new line) into the connection.
+func (conn *Conn) write(data []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	if conn.closed() {
+		return ErrClosed
+	}
+
+	_, err := conn.rwc.Write(append(data, '\n'))
+
+	return err
+}
@@ -0,0 +1,201 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+type notifyMethod struct{}
+
+func (*notifyMethod) GetParamsType() interface{} { return nil }
+func (*notifyMethod) GetName() string            { return "notify" }
+func (*notifyMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+func (*notifyMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	s, _ := session.FromContext(ctx)
+
+	_ = s.Notify("hello", []string{s.ID()})
+
+	return "ok", nil
+}
+
+type metadataMethod struct{}
+
+func (*metadataMethod) GetParamsType() interface{} { return nil }
+func (*metadataMethod) GetName() string            { return "metadata" }
+func (*metadataMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+func (*metadataMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	md, _ := metadata.FromContext(ctx)
+
+	return []string{md.Transport, md.SessionID, md.RemoteAddr}, nil
+}
+
+type blockingMethod struct {
+	started, canceled chan struct{}
+}
+
+func (*blockingMethod) GetParamsType() interface{} { return nil }
+func (*blockingMethod) GetName() string            { return "block" }
+func (*blockingMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+func (m *blockingMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	close(m.started)
+	<-ctx.Done()
+	close(m.canceled)
+
+	return nil, nil
+}
+
+func newTestConn(t *testing.T, methods ...jsonrpc.Method) (*Conn, net.Conn) {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethods(append(methods, &notifyMethod{}, &metadataMethod{})...))
+
+	server, client := net.Pipe()
+
+	return NewConn(rpcKernel.New(router), server), client
+}
+
+func TestConn_Serve(t *testing.T) {
+	t.Parallel()
+
+	conn, client := newTestConn(t)
+
+	served := make(chan error, 1)
+
+	go func() { served <- conn.Serve(context.Background()) }()
+
+	reader := bufio.NewReader(client)
+
+	_, _ = client.Write([]byte(`{"jsonrpc": "2.0", "method": "notify", "id": 1}`))
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "method": "hello", "params": ["`+conn.ID()+`"]}`, line)
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": "ok", "id": 1}`, line)
+
+	// notifications without responses, then a batch
+	_, _ = client.Write([]byte(`{"jsonrpc": "2.0", "method": "foo"}`))
+	_, _ = client.Write([]byte(`[{"jsonrpc": "2.0", "method": "foo", "id": 2}]`))
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"jsonrpc": "2.0", "error": {"code": -32601, "message": "Method not found"}, "id": 2}]`, line)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, <-served)
+
+	<-conn.Done()
+
+	assert.Equal(t, ErrClosed, conn.Notify("foo", nil))
+}
+
+func TestConn_ServeMetadata(t *testing.T) {
+	t.Parallel()
+
+	conn, client := newTestConn(t)
+
+	go func() { _ = conn.Serve(context.Background()) }()
+
+	defer client.Close()
+
+	_, _ = client.Write([]byte(`{"jsonrpc": "2.0", "method": "metadata", "id": 1}`))
+
+	line, err := bufio.NewReader(client).ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": ["stream", "`+conn.ID()+`", "pipe"], "id": 1}`, line)
+}
+
+func TestConn_ServeParseError(t *testing.T) {
+	t.Parallel()
+
+	conn, client := newTestConn(t)
+
+	served := make(chan error, 1)
+
+	go func() { served <- conn.Serve(context.Background()) }()
+
+	_, _ = client.Write([]byte(`{"foo"]`))
+
+	line, err := bufio.NewReader(client).ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "error": {"code": -32700, "message": "Parse error"}}`, line)
+
+	assert.Error(t, <-served)
+	<-conn.Done()
+}
+
+func TestConn_ServeContextCanceling(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := newTestConn(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+
+	go func() { served <- conn.Serve(ctx) }()
+
+	cancel()
+
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("connection was not closed")
+	}
+
+	<-conn.Done()
+}
+
+func TestConn_ServeDisconnectCancelsRequests(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method       = &blockingMethod{started: make(chan struct{}), canceled: make(chan struct{})}
+		conn, client = newTestConn(t, method)
+		served       = make(chan error, 1)
+	)
+
+	go func() { served <- conn.Serve(context.Background()) }()
+
+	_, _ = client.Write([]byte(`{"jsonrpc": "2.0", "method": "block", "id": 1}`))
+
+	<-method.started
+
+	assert.NoError(t, client.Close())
+
+	select {
+	case <-method.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("request was not canceled")
+	}
+
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("serving was not stopped")
+	}
+
+	<-conn.Done()
+}
@@ -0,0 +1,153 @@
+// Package subscription implements server-to-client events subscriptions (like `eth_subscribe`/`eth_unsubscribe`).
+// Subscriptions are bound to the client session (see session package), and are removed automatically when the
+// session is closed.
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// DefaultNotificationMethod is the default method name of the subscription notifications.
+const DefaultNotificationMethod = "subscription"
+
+var (
+	// ErrUnknownTopic is returned on subscription to the unregistered topic.
+	ErrUnknownTopic = errors.New("jsonrpc: unknown subscription topic")
+
+	// ErrTopicAlreadyRegistered is returned on the topic registration with already used name.
+	ErrTopicAlreadyRegistered = errors.New("jsonrpc: subscription topic already registered")
+)
+
+// Topic is called on each subscription to the topic. It can validate subscription arguments (by returning an error)
+// or start events producing (in a separate goroutine, until the subscription Done channel is closed).
+type Topic func(sub *Subscription) error
+
+// Manager manages subscriptions.
+type Manager struct {
+	mutex         sync.RWMutex
+	topics        map[string]Topic
+	subscriptions map[string]*Subscription
+
+	// NotificationMethod is used as method name of the notifications (DefaultNotificationMethod by default).
+	NotificationMethod string
+}
+
+// NewManager creates new subscriptions manager.
+func NewManager() *Manager {
+	return &Manager{
+		topics:             make(map[string]Topic),
+		subscriptions:      make(map[string]*Subscription),
+		NotificationMethod: DefaultNotificationMethod,
+	}
+}
+
+// RegisterTopic registers the topic, available for subscription. onSubscribe can be nil.
+func (manager *Manager) RegisterTopic(name string, onSubscribe Topic) error {
+	if name == "" {
+		return errors.New("jsonrpc: empty topic name")
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if _, exists := manager.topics[name]; exists {
+		return fmt.Errorf("%w: %s", ErrTopicAlreadyRegistered, name)
+	}
+
+	manager.topics[name] = onSubscribe
+
+	return nil
+}
+
+// Subscribe creates new subscription to the topic for the session.
+func (manager *Manager) Subscribe(s session.Session, topic string, args []interface{}) (*Subscription, error) {
+	manager.mutex.RLock()
+	onSubscribe, exists := manager.topics[topic]
+	manager.mutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTopic, topic)
+	}
+
+	sub := &Subscription{
+		ID:      session.NewID(),
+		Topic:   topic,
+		Args:    args,
+		session: s,
+		manager: manager,
+		done:    make(chan struct{}),
+	}
+
+	if onSubscribe != nil {
+		if err := onSubscribe(sub); err != nil {
+			return nil, err
+		}
+	}
+
+	manager.mutex.Lock()
+	manager.subscriptions[sub.ID] = sub
+	manager.mutex.Unlock()
+
+	go func() {
+		select {
+		case <-s.Done():
+			sub.close()
+		case <-sub.done:
+		}
+	}()
+
+	return sub, nil
+}
+
+// Unsubscribe cancels the subscription. It returns false when subscription was not found.
+func (manager *Manager) Unsubscribe(id string) bool {
+	manager.mutex.RLock()
+	sub, exists := manager.subscriptions[id]
+	manager.mutex.RUnlock()
+
+	if exists {
+		sub.close()
+	}
+
+	return exists
+}
+
+// Subscriptions returns active subscriptions to the topic.
+func (manager *Manager) Subscriptions(topic string) []*Subscription {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	result := make([]*Subscription, 0)
+
+	for _, sub := range manager.subscriptions {
+		if sub.Topic == topic {
+			result = append(result, sub)
+		}
+	}
+
+	return result
+}
+
+// Publish sends the result to all subscribers of the topic. It returns the number of successfully sent
+// notifications.
+func (manager *Manager) Publish(topic string, result interface{}) int {
+	var sent int
+
+	for _, sub := range manager.Subscriptions(topic) {
+		if err := sub.Notify(result); err == nil {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+func (manager *Manager) remove(id string) {
+	manager.mutex.Lock()
+	delete(manager.subscriptions, id)
+	manager.mutex.Unlock()
+}
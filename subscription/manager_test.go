@@ -0,0 +1,107 @@
+package subscription
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_RegisterTopic(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager()
+
+	assert.NoError(t, manager.RegisterTopic("foo", nil))
+	assert.True(t, errors.Is(manager.RegisterTopic("foo", nil), ErrTopicAlreadyRegistered))
+	assert.Error(t, manager.RegisterTopic("", nil))
+}
+
+func TestManager_SubscribeAndPublish(t *testing.T) {
+	t.Parallel()
+
+	var (
+		manager = NewManager()
+		s1      = newFakeSession("1")
+		s2      = newFakeSession("2")
+	)
+
+	assert.NoError(t, manager.RegisterTopic("foo", nil))
+	assert.NoError(t, manager.RegisterTopic("bar", nil))
+
+	sub1, err := manager.Subscribe(s1, "foo", nil)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, sub1.ID)
+	assert.Equal(t, s1, sub1.Session())
+
+	sub2, err := manager.Subscribe(s2, "foo", []interface{}{1})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1}, sub2.Args)
+
+	_, err = manager.Subscribe(s2, "bar", nil)
+	assert.NoError(t, err)
+
+	_, err = manager.Subscribe(s1, "baz", nil)
+	assert.True(t, errors.Is(err, ErrUnknownTopic))
+
+	assert.Equal(t, 2, manager.Publish("foo", "event"))
+	assert.Equal(t, []notification{
+		{method: DefaultNotificationMethod, params: Notification{Subscription: sub1.ID, Result: "event"}},
+	}, s1.received())
+	assert.Len(t, s2.received(), 1)
+
+	assert.True(t, manager.Unsubscribe(sub1.ID))
+	assert.False(t, manager.Unsubscribe(sub1.ID))
+
+	<-sub1.Done()
+
+	assert.Equal(t, 1, manager.Publish("foo", "event"))
+	assert.Len(t, s1.received(), 1)
+}
+
+func TestManager_SubscribeHook(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager()
+
+	assert.NoError(t, manager.RegisterTopic("foo", func(sub *Subscription) error {
+		if len(sub.Args) == 0 {
+			return errors.New("args required")
+		}
+
+		return nil
+	}))
+
+	_, err := manager.Subscribe(newFakeSession("1"), "foo", nil)
+	assert.EqualError(t, err, "args required")
+	assert.Empty(t, manager.Subscriptions("foo"))
+
+	_, err = manager.Subscribe(newFakeSession("1"), "foo", []interface{}{true})
+	assert.NoError(t, err)
+	assert.Len(t, manager.Subscriptions("foo"), 1)
+}
+
+func TestManager_CleanupOnSessionClosing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		manager = NewManager()
+		s       = newFakeSession("1")
+	)
+
+	assert.NoError(t, manager.RegisterTopic("foo", nil))
+
+	sub, err := manager.Subscribe(s, "foo", nil)
+	assert.NoError(t, err)
+
+	close(s.done)
+
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+
+	assert.Empty(t, manager.Subscriptions("foo"))
+}
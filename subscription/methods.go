@@ -0,0 +1,109 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// SubscribeMethod returns a method, that creates subscriptions. Method params are `["topic", args...]`, and the
+// result is a subscription ID. Method requires a client session in the context (persistent connection).
+func (manager *Manager) SubscribeMethod(name string) jsonrpc.Method {
+	return &subscribeMethod{name: name, manager: manager}
+}
+
+// UnsubscribeMethod returns a method, that cancels subscriptions. Method params are `["subscription ID"]`, and the
+// result is `true` when the subscription (created in the same session) was canceled.
+func (manager *Manager) UnsubscribeMethod(name string) jsonrpc.Method {
+	return &unsubscribeMethod{name: name, manager: manager}
+}
+
+func sessionRequired() *rpcErrors.Error {
+	err := rpcErrors.New(rpcErrors.InvalidRequest)
+	err.Data = "subscriptions require a persistent connection"
+
+	return err
+}
+
+func invalidParams(data string) *rpcErrors.Error {
+	err := rpcErrors.New(rpcErrors.InvalidParams)
+	err.Data = data
+
+	return err
+}
+
+type subscribeMethod struct {
+	name    string
+	manager *Manager
+}
+
+func (m *subscribeMethod) GetName() string            { return m.name }
+func (m *subscribeMethod) GetParamsType() interface{} { return new([]interface{}) }
+func (m *subscribeMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, sessionRequired()
+}
+
+func (m *subscribeMethod) HandleContext(ctx context.Context, params interface{}) (interface{}, jsonrpc.Error) {
+	s, ok := session.FromContext(ctx)
+	if !ok {
+		return nil, sessionRequired()
+	}
+
+	args, _ := params.(*[]interface{})
+	if args == nil || len(*args) == 0 {
+		return nil, invalidParams("topic name is required")
+	}
+
+	topic, ok := (*args)[0].(string)
+	if !ok {
+		return nil, invalidParams("topic name should be a string")
+	}
+
+	sub, err := m.manager.Subscribe(s, topic, (*args)[1:])
+	if err != nil {
+		var rpcErr jsonrpc.Error
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+
+		return nil, invalidParams(err.Error())
+	}
+
+	return sub.ID, nil
+}
+
+type unsubscribeMethod struct {
+	name    string
+	manager *Manager
+}
+
+func (m *unsubscribeMethod) GetName() string            { return m.name }
+func (m *unsubscribeMethod) GetParamsType() interface{} { return new([]string) }
+func (m *unsubscribeMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, sessionRequired()
+}
+
+func (m *unsubscribeMethod) HandleContext(ctx context.Context, params interface{}) (interface{}, jsonrpc.Error) {
+	s, ok := session.FromContext(ctx)
+	if !ok {
+		return nil, sessionRequired()
+	}
+
+	ids, _ := params.(*[]string)
+	if ids == nil || len(*ids) != 1 {
+		return nil, invalidParams("subscription ID is required")
+	}
+
+	m.manager.mutex.RLock()
+	sub, exists := m.manager.subscriptions[(*ids)[0]]
+	m.manager.mutex.RUnlock()
+
+	if !exists || sub.session.ID() != s.ID() { // subscriptions of other sessions are not visible
+		return false, nil
+	}
+
+	return m.manager.Unsubscribe(sub.ID), nil
+}
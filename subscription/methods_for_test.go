@@ -0,0 +1,40 @@
+package subscription
+
+import (
+	"sync"
+)
+
+type notification struct {
+	method string
+	params interface{}
+}
+
+type fakeSession struct {
+	id   string
+	done chan struct{}
+
+	mutex         sync.Mutex
+	notifications []notification
+}
+
+func newFakeSession(id string) *fakeSession {
+	return &fakeSession{id: id, done: make(chan struct{})}
+}
+
+func (s *fakeSession) ID() string            { return s.id }
+func (s *fakeSession) Done() <-chan struct{} { return s.done }
+func (s *fakeSession) Notify(method string, params interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.notifications = append(s.notifications, notification{method: method, params: params})
+
+	return nil
+}
+
+func (s *fakeSession) received() []notification {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]notification(nil), s.notifications...)
+}
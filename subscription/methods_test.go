@@ -0,0 +1,139 @@
+package subscription
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+	"github.com/tarampampam/go-jsonrpc/session"
+	"github.com/tarampampam/go-jsonrpc/stream"
+)
+
+func TestSubscribeMethod(t *testing.T) {
+	t.Parallel()
+
+	var (
+		manager = NewManager()
+		router  = rpcRouter.New()
+		ctx     = session.WithSession(context.Background(), newFakeSession("1"))
+	)
+
+	assert.NoError(t, manager.RegisterTopic("foo", nil))
+	assert.NoError(t, router.RegisterMethods(
+		manager.SubscribeMethod("subscribe"),
+		manager.UnsubscribeMethod("unsubscribe"),
+	))
+
+	cases := []struct {
+		name     string
+		giveCtx  context.Context
+		giveArgs interface{}
+		wantCode rpcErrors.Code
+	}{
+		{name: "success", giveCtx: ctx, giveArgs: []interface{}{"foo", 1}},
+		{name: "without session", giveCtx: context.Background(), giveArgs: []interface{}{"foo"}, wantCode: -32600},
+		{name: "without topic", giveCtx: ctx, giveArgs: []interface{}{}, wantCode: -32602},
+		{name: "wrong topic type", giveCtx: ctx, giveArgs: []interface{}{1}, wantCode: -32602},
+		{name: "unknown topic", giveCtx: ctx, giveArgs: []interface{}{"bar"}, wantCode: -32602},
+		{name: "wrong params", giveCtx: ctx, giveArgs: map[string]string{"foo": "bar"}, wantCode: -32602},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := router.InvokeContext(tt.giveCtx, "subscribe", tt.giveArgs)
+
+			if tt.wantCode == 0 {
+				assert.Nil(t, err)
+				assert.IsType(t, "", res)
+			} else {
+				assert.Nil(t, res)
+				assert.Equal(t, int(tt.wantCode), err.GetCode())
+			}
+		})
+	}
+}
+
+func TestUnsubscribeMethod(t *testing.T) {
+	t.Parallel()
+
+	var (
+		manager = NewManager()
+		router  = rpcRouter.New()
+		ctx1    = session.WithSession(context.Background(), newFakeSession("1"))
+		ctx2    = session.WithSession(context.Background(), newFakeSession("2"))
+	)
+
+	assert.NoError(t, manager.RegisterTopic("foo", nil))
+	assert.NoError(t, router.RegisterMethods(
+		manager.SubscribeMethod("subscribe"),
+		manager.UnsubscribeMethod("unsubscribe"),
+	))
+
+	id, err := router.InvokeContext(ctx1, "subscribe", []string{"foo"})
+	assert.Nil(t, err)
+
+	res, err := router.InvokeContext(ctx2, "unsubscribe", []interface{}{id}) // other session
+	assert.Nil(t, err)
+	assert.Equal(t, false, res)
+
+	res, err = router.InvokeContext(ctx1, "unsubscribe", []interface{}{id})
+	assert.Nil(t, err)
+	assert.Equal(t, true, res)
+
+	res, err = router.InvokeContext(ctx1, "unsubscribe", []interface{}{id})
+	assert.Nil(t, err)
+	assert.Equal(t, false, res)
+
+	_, err = router.InvokeContext(ctx1, "unsubscribe", []interface{}{})
+	assert.Equal(t, int(rpcErrors.InvalidParams), err.GetCode())
+
+	_, err = router.InvokeContext(context.Background(), "unsubscribe", []interface{}{id})
+	assert.Equal(t, int(rpcErrors.InvalidRequest), err.GetCode())
+}
+
+func TestSubscriptionsOverStream(t *testing.T) {
+	t.Parallel()
+
+	var (
+		manager        = NewManager()
+		router         = rpcRouter.New()
+		server, client = net.Pipe()
+		conn           = stream.NewConn(rpcKernel.New(router), server)
+		reader         = bufio.NewReader(client)
+	)
+
+	manager.NotificationMethod = "eth_subscription"
+
+	assert.NoError(t, manager.RegisterTopic("newHeads", nil))
+	assert.NoError(t, router.RegisterMethods(
+		manager.SubscribeMethod("eth_subscribe"),
+		manager.UnsubscribeMethod("eth_unsubscribe"),
+	))
+
+	go func() { _ = conn.Serve(context.Background()) }()
+
+	_, _ = client.Write([]byte(`{"jsonrpc": "2.0", "method": "eth_subscribe", "params": ["newHeads"], "id": 1}`))
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+
+	subs := manager.Subscriptions("newHeads")
+	assert.Len(t, subs, 1)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": "`+subs[0].ID+`", "id": 1}`, line)
+
+	go manager.Publish("newHeads", map[string]int{"number": 1})
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "method": "eth_subscription", "params": {"subscription": "`+
+		subs[0].ID+`", "result": {"number": 1}}}`, line)
+
+	assert.NoError(t, client.Close())
+
+	<-subs[0].Done()
+}
@@ -0,0 +1,43 @@
+package subscription
+
+import (
+	"sync"
+
+	"github.com/tarampampam/go-jsonrpc/session"
+)
+
+// Subscription is an active subscription to the topic.
+type Subscription struct {
+	ID    string        // unique subscription ID
+	Topic string        // topic name
+	Args  []interface{} // subscription arguments (params after the topic name)
+
+	session   session.Session
+	manager   *Manager
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Notification is the subscription notification params.
+type Notification struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// Session returns the subscriber session.
+func (sub *Subscription) Session() session.Session { return sub.session }
+
+// Done returns a channel, that is closed when subscription is canceled (or subscriber session is closed).
+func (sub *Subscription) Done() <-chan struct{} { return sub.done }
+
+// Notify sends the result to the subscriber.
+func (sub *Subscription) Notify(result interface{}) error {
+	return sub.session.Notify(sub.manager.NotificationMethod, Notification{Subscription: sub.ID, Result: result})
+}
+
+func (sub *Subscription) close() {
+	sub.closeOnce.Do(func() {
+		close(sub.done)
+		sub.manager.remove(sub.ID)
+	})
+}
@@ -0,0 +1,153 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRequest "github.com/tarampampam/go-jsonrpc/request"
+	rpcResponse "github.com/tarampampam/go-jsonrpc/response"
+)
+
+// MetaField is a reserved request params field for the trace context propagation (when the transport has no
+// headers), e.g.: `"params": {"_meta": {"traceparent": "00-..."}, ...}`.
+const MetaField = "_meta"
+
+// Span names and attributes keys.
+const (
+	BatchSpanName = "jsonrpc.batch"
+
+	AttrSystem       = "rpc.system"
+	AttrMethod       = "rpc.method"
+	AttrVersion      = "rpc.jsonrpc.version"
+	AttrRequestID    = "rpc.jsonrpc.request_id"
+	AttrErrorCode    = "rpc.jsonrpc.error_code"
+	AttrErrorMessage = "rpc.jsonrpc.error_message"
+	AttrBatchSize    = "rpc.jsonrpc.batch_size"
+)
+
+// Instrument registers kernel PayloadHook and Middleware.
+func Instrument(kernel *rpcKernel.Kernel, tracer Tracer) {
+	kernel.Hook(PayloadHook(tracer))
+	kernel.Use(Middleware(tracer))
+}
+
+// PayloadHook returns kernel payload hook, that starts a span per batch (batch requests spans are its children).
+func PayloadHook(tracer Tracer) rpcKernel.PayloadHook {
+	return func(ctx context.Context, payload rpcKernel.Payload) (context.Context, func()) {
+		if !payload.Batch {
+			return ctx, nil
+		}
+
+		ctx, span := tracer.Start(ctx, BatchSpanName)
+		span.SetAttribute(AttrSystem, "jsonrpc")
+		span.SetAttribute(AttrBatchSize, payload.Requests)
+
+		return ctx, span.End
+	}
+}
+
+// Middleware returns kernel middleware, that starts a span per request. Trace context from the request params
+// MetaField has priority over the parent span from the context.
+func Middleware(tracer Tracer) rpcKernel.Middleware {
+	return func(next rpcKernel.RequestHandler) rpcKernel.RequestHandler {
+		return func(ctx context.Context, request rpcRequest.Request) *rpcResponse.Response {
+			if sc, ok := extractParams(request.Params); ok {
+				// nil span hides the parent span (e.g. batch span) from the tracer
+				ctx = ContextWithSpan(ContextWithRemoteParent(ctx, sc), nil)
+			}
+
+			var name = request.Method
+			if name == "" {
+				name = "jsonrpc.request"
+			}
+
+			ctx, span := tracer.Start(ctx, name)
+			defer span.End()
+
+			span.SetAttribute(AttrSystem, "jsonrpc")
+			span.SetAttribute(AttrMethod, request.Method)
+			span.SetAttribute(AttrVersion, request.Version)
+
+			if request.ID != nil {
+				span.SetAttribute(AttrRequestID, fmt.Sprint(request.ID))
+			}
+
+			response := next(ctx, request)
+
+			if response != nil && response.Error != nil {
+				span.SetAttribute(AttrErrorCode, int(response.Error.Code))
+				span.SetAttribute(AttrErrorMessage, response.Error.Message)
+				span.SetError(response.Error.Message)
+			}
+
+			return response
+		}
+	}
+}
+
+// HTTPMiddleware extracts the trace context from the HTTP request header.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sc, err := ParseTraceParent(r.Header.Get(TraceParentHeader)); err == nil {
+			r = r.WithContext(ContextWithRemoteParent(r.Context(), sc))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Inject sets the trace context header (for the outgoing HTTP requests).
+func Inject(ctx context.Context, header http.Header) {
+	if sc, ok := current(ctx); ok {
+		header.Set(TraceParentHeader, sc.TraceParent())
+	}
+}
+
+// InjectParams sets the trace context into the MetaField of the outgoing request params.
+func InjectParams(ctx context.Context, params map[string]interface{}) {
+	sc, ok := current(ctx)
+	if !ok {
+		return
+	}
+
+	meta, ok := params[MetaField].(map[string]interface{})
+	if !ok {
+		meta = make(map[string]interface{}, 1)
+		params[MetaField] = meta
+	}
+
+	meta[TraceParentHeader] = sc.TraceParent()
+}
+
+// current returns the context span (or remote parent) span context.
+func current(ctx context.Context) (SpanContext, bool) {
+	if span, ok := SpanFromContext(ctx); ok {
+		return span.SpanContext(), true
+	}
+
+	return RemoteParentFromContext(ctx)
+}
+
+// extractParams extracts the trace context from the request params MetaField.
+func extractParams(params interface{}) (SpanContext, bool) {
+	p, ok := params.(map[string]interface{})
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	meta, ok := p[MetaField].(map[string]interface{})
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	value, ok := meta[TraceParentHeader].(string)
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	sc, err := ParseTraceParent(value)
+
+	return sc, err == nil
+}
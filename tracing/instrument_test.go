@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	"github.com/tarampampam/go-jsonrpc/httphandler"
+	rpcKernel "github.com/tarampampam/go-jsonrpc/kernel"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type injectMethod struct{}
+
+func (*injectMethod) GetParamsType() interface{} { return nil }
+func (*injectMethod) GetName() string            { return "inject" }
+func (*injectMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return nil, nil
+}
+
+// HandleContext returns the outgoing requests trace context.
+func (*injectMethod) HandleContext(ctx context.Context, _ interface{}) (interface{}, jsonrpc.Error) {
+	var (
+		header = http.Header{}
+		params = map[string]interface{}{}
+	)
+
+	Inject(ctx, header)
+	InjectParams(ctx, params)
+
+	return []interface{}{header.Get(TraceParentHeader), params[MetaField]}, nil
+}
+
+func newTestKernel(t *testing.T) (*rpcKernel.Kernel, *MemoryTracer) {
+	router := rpcRouter.New()
+
+	assert.NoError(t, router.RegisterMethod(&injectMethod{}))
+
+	var (
+		kernel = rpcKernel.New(router)
+		tracer = NewMemoryTracer()
+	)
+
+	Instrument(kernel, tracer)
+
+	return kernel, tracer
+}
+
+func TestInstrumentSingleRequest(t *testing.T) {
+	t.Parallel()
+
+	kernel, tracer := newTestKernel(t)
+
+	kernel.HandleJSONRequest([]byte(`{"jsonrpc": "2.0", "method": "foo", "id": 1}`))
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "foo", spans[0].Name)
+	assert.True(t, spans[0].Ended)
+	assert.False(t, spans[0].Parent.IsValid())
+	assert.Equal(t, "Method not found", spans[0].Error)
+	assert.Equal(t, map[string]interface{}{
+		AttrSystem:       "jsonrpc",
+		AttrMethod:       "foo",
+		AttrVersion:      "2.0",
+		AttrRequestID:    "1",
+		AttrErrorCode:    -32601,
+		AttrErrorMessage: "Method not found",
+	}, spans[0].Attributes)
+}
+
+func TestInstrumentBatch(t *testing.T) {
+	t.Parallel()
+
+	kernel, tracer := newTestKernel(t)
+
+	kernel.HandleJSONRequest([]byte(`[
+		{"jsonrpc": "2.0", "method": "inject", "id": 1},
+		{"jsonrpc": "2.0", "method": "inject", "params": {"_meta": {"traceparent": "` + testTraceParent + `"}}, "id": 2}
+	]`))
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 3)
+
+	batch := spans[0]
+	assert.Equal(t, BatchSpanName, batch.Name)
+	assert.Equal(t, 2, batch.Attributes[AttrBatchSize])
+	assert.True(t, batch.Ended)
+
+	for _, span := range spans[1:] {
+		assert.True(t, span.Ended)
+
+		switch span.Attributes[AttrRequestID] {
+		case "1":
+			assert.Equal(t, batch.Context, span.Parent)
+		case "2": // trace context from the params
+			assert.Equal(t, testTraceParent, span.Parent.TraceParent())
+		default:
+			t.Error("unexpected span")
+		}
+	}
+}
+
+func TestHTTPMiddlewareAndInject(t *testing.T) {
+	t.Parallel()
+
+	var (
+		kernel, tracer = newTestKernel(t)
+		req, _         = http.NewRequest(http.MethodPost, "http://rpc/", strings.NewReader(
+			`{"jsonrpc": "2.0", "method": "inject", "id": 1}`,
+		))
+		rr = httptest.NewRecorder()
+	)
+
+	req.Header.Set(TraceParentHeader, testTraceParent)
+
+	HTTPMiddleware(httphandler.New(kernel)).ServeHTTP(rr, req)
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, testTraceParent, spans[0].Parent.TraceParent())
+	assert.Equal(t, spans[0].Parent.TraceID, spans[0].Context.TraceID)
+
+	traceParent := spans[0].Context.TraceParent()
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "result": [
+		"`+traceParent+`", {"traceparent": "`+traceParent+`"}
+	], "id": 1}`, rr.Body.String())
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		header = http.Header{}
+		params = map[string]interface{}{}
+	)
+
+	Inject(context.Background(), header)
+	InjectParams(context.Background(), params)
+
+	assert.Empty(t, header)
+	assert.Empty(t, params)
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTracer is a Tracer, that keeps spans in memory (useful for tests).
+type MemoryTracer struct {
+	mutex sync.Mutex
+	spans []*MemorySpan
+}
+
+// NewMemoryTracer creates new in-memory tracer.
+func NewMemoryTracer() *MemoryTracer { return &MemoryTracer{} }
+
+// Start implements Tracer interface.
+func (t *MemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &MemorySpan{Name: name, Attributes: make(map[string]interface{})}
+
+	if parent, ok := SpanFromContext(ctx); ok {
+		span.Parent = parent.SpanContext()
+	} else if remote, ok := RemoteParentFromContext(ctx); ok {
+		span.Parent = remote
+	}
+
+	span.Context = SpanContext{TraceID: span.Parent.TraceID, SpanID: NewSpanID(), Sampled: true}
+
+	if !span.Parent.IsValid() {
+		span.Context.TraceID = NewTraceID()
+	}
+
+	t.mutex.Lock()
+	t.spans = append(t.spans, span)
+	t.mutex.Unlock()
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Spans returns all started spans (in the order of starting).
+func (t *MemoryTracer) Spans() []*MemorySpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return append([]*MemorySpan(nil), t.spans...)
+}
+
+// MemorySpan is a span of the MemoryTracer.
+type MemorySpan struct {
+	mutex sync.Mutex
+
+	Name       string
+	Context    SpanContext
+	Parent     SpanContext // zero for the root spans
+	Attributes map[string]interface{}
+	Error      string
+	Ended      bool
+}
+
+// SetAttribute implements Span interface.
+func (s *MemorySpan) SetAttribute(key string, value interface{}) {
+	s.mutex.Lock()
+	s.Attributes[key] = value
+	s.mutex.Unlock()
+}
+
+// SetError implements Span interface.
+func (s *MemorySpan) SetError(message string) {
+	s.mutex.Lock()
+	s.Error = message
+	s.mutex.Unlock()
+}
+
+// SpanContext implements Span interface.
+func (s *MemorySpan) SpanContext() SpanContext { return s.Context }
+
+// End implements Span interface.
+func (s *MemorySpan) End() {
+	s.mutex.Lock()
+	s.Ended = true
+	s.mutex.Unlock()
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// TraceParentHeader is the W3C trace context header name.
+const TraceParentHeader = "traceparent"
+
+// ErrInvalidTraceParent is returned on the traceparent parsing error.
+var ErrInvalidTraceParent = errors.New("jsonrpc: invalid traceparent")
+
+type (
+	// TraceID is a trace identifier.
+	TraceID [16]byte
+
+	// SpanID is a span identifier.
+	SpanID [8]byte
+)
+
+// SpanContext is a W3C trace context (https://www.w3.org/TR/trace-context/).
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid checks if trace and span IDs are not zero.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// TraceParent formats the span context as a traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	var flags = "00"
+
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceParent parses traceparent header value (`version-traceid-spanid-flags`).
+func ParseTraceParent(value string) (SpanContext, error) {
+	const length = 55 // 2 + 1 + 32 + 1 + 16 + 1 + 2
+
+	if len(value) < length || (len(value) > length && (value[:2] == "00" || value[length] != '-')) {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' || value[:2] == "ff" {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	var (
+		sc    SpanContext
+		flags [1]byte
+	)
+
+	if _, err := hex.Decode(flags[:], []byte(value[53:55])); err != nil {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(value[3:35])); err != nil {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(value[36:52])); err != nil {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+
+	return sc, nil
+}
+
+// NewTraceID generates random trace ID.
+func NewTraceID() (id TraceID) {
+	_, _ = rand.Read(id[:])
+
+	return
+}
+
+// NewSpanID generates random span ID.
+func NewSpanID() (id SpanID) {
+	_, _ = rand.Read(id[:])
+
+	return
+}
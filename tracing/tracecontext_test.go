@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		give        string
+		wantSampled bool
+		wantErr     bool
+	}{
+		{name: "sampled", give: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantSampled: true},
+		{name: "not sampled", give: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version", give: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-foo", wantSampled: true},
+		{name: "empty", give: "", wantErr: true},
+		{name: "too long for version 00", give: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-", wantErr: true},
+		{name: "forbidden version", give: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero trace ID", give: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero span ID", give: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{name: "wrong hex", give: "00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01", wantErr: true},
+		{name: "wrong separator", give: "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceParent(tt.give)
+
+			if tt.wantErr {
+				assert.Equal(t, ErrInvalidTraceParent, err)
+				assert.False(t, sc.IsValid())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, sc.IsValid())
+			assert.Equal(t, tt.wantSampled, sc.Sampled)
+			assert.Equal(t, tt.give[3:52], sc.TraceParent()[3:52])
+		})
+	}
+}
+
+func TestSpanContext_TraceParent(t *testing.T) {
+	t.Parallel()
+
+	sc := SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}, Sampled: true}
+
+	assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-01", sc.TraceParent())
+
+	sc.Sampled = false
+
+	assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-00", sc.TraceParent())
+}
+
+func TestNewIDs(t *testing.T) {
+	t.Parallel()
+
+	assert.NotEqual(t, NewTraceID(), NewTraceID())
+	assert.NotEqual(t, NewSpanID(), NewSpanID())
+}
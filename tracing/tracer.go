@@ -0,0 +1,61 @@
+// Package tracing implements distributed tracing of the kernel requests with W3C trace context propagation. Tracer
+// interface is small enough to be implemented using OpenTelemetry, and MemoryTracer can be used in tests.
+//
+//	tracing.Instrument(kernel, tracer)
+//	http.Handle("/rpc", tracing.HTTPMiddleware(httphandler.New(kernel)))
+package tracing
+
+import "context"
+
+type (
+	// Tracer starts spans.
+	Tracer interface {
+		// Start starts new span. Span from the context (see SpanFromContext) or remote parent span context (see
+		// RemoteParentFromContext) should be used as a parent. Returned context should contain the started span.
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+
+	// Span is a started span.
+	Span interface {
+		// SetAttribute sets span attribute.
+		SetAttribute(key string, value interface{})
+
+		// SetError marks the span as failed.
+		SetError(message string)
+
+		// SpanContext returns the span context (for propagation).
+		SpanContext() SpanContext
+
+		// End ends the span.
+		End()
+	}
+)
+
+type (
+	spanCtxKey         struct{}
+	remoteParentCtxKey struct{}
+)
+
+// ContextWithSpan returns a context with attached span.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanCtxKey{}, span)
+}
+
+// SpanFromContext returns the span, attached to the context using ContextWithSpan.
+func SpanFromContext(ctx context.Context) (Span, bool) {
+	span, ok := ctx.Value(spanCtxKey{}).(Span)
+
+	return span, ok && span != nil
+}
+
+// ContextWithRemoteParent returns a context with remote parent span context (extracted from the incoming request).
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteParentCtxKey{}, sc)
+}
+
+// RemoteParentFromContext returns remote parent span context, attached using ContextWithRemoteParent.
+func RemoteParentFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(remoteParentCtxKey{}).(SpanContext)
+
+	return sc, ok && sc.IsValid()
+}
@@ -0,0 +1,171 @@
+// Package xmlrpc provides XML-RPC <http://xmlrpc.com/spec.md> transport for the Router.
+package xmlrpc
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeLayout is a layout of `dateTime.iso8601` values.
+const dateTimeLayout = "20060102T15:04:05"
+
+type (
+	methodCall struct {
+		XMLName    xml.Name `xml:"methodCall"`
+		MethodName string   `xml:"methodName"`
+		Params     []value  `xml:"params>param>value"`
+	}
+
+	value struct {
+		Int      *string      `xml:"int"`
+		I4       *string      `xml:"i4"`
+		I8       *string      `xml:"i8"`
+		Double   *string      `xml:"double"`
+		Boolean  *string      `xml:"boolean"`
+		String   *string      `xml:"string"`
+		Base64   *string      `xml:"base64"`
+		DateTime *string      `xml:"dateTime.iso8601"`
+		Struct   *structValue `xml:"struct"`
+		Array    *arrayValue  `xml:"array"`
+		Nil      *struct{}    `xml:"nil"`
+		Text     string       `xml:",chardata"` // value without type is a string
+	}
+
+	structValue struct {
+		Members []member `xml:"member"`
+	}
+
+	member struct {
+		Name  string `xml:"name"`
+		Value value  `xml:"value"`
+	}
+
+	arrayValue struct {
+		Values []value `xml:"data>value"`
+	}
+)
+
+// DecodeMethodCall reads `methodCall` document and returns method name and params in the form, that Router
+// expects: single struct param will be returned as an object (named params), and any other params - as an array
+// (positional params). Values are converted into the Go types: `int`, `i4` and `i8` - int64, `double` - float64,
+// `boolean` - bool, `string` - string, `base64` - []byte, `dateTime.iso8601` - time.Time, `struct` - map,
+// `array` - slice.
+func DecodeMethodCall(r io.Reader) (methodName string, params interface{}, err error) {
+	var call methodCall
+
+	if err = xml.NewDecoder(r).Decode(&call); err != nil {
+		return
+	}
+
+	if methodName = strings.TrimSpace(call.MethodName); methodName == "" {
+		err = errors.New("xmlrpc: empty method name")
+
+		return
+	}
+
+	if len(call.Params) == 0 {
+		return
+	}
+
+	positional := make([]interface{}, 0, len(call.Params))
+
+	for _, v := range call.Params {
+		converted, convErr := v.convert()
+		if convErr != nil {
+			return "", nil, convErr
+		}
+
+		positional = append(positional, converted)
+	}
+
+	if object, ok := positional[0].(map[string]interface{}); ok && len(positional) == 1 {
+		return methodName, object, nil
+	}
+
+	return methodName, positional, nil
+}
+
+// convert converts XML-RPC value into the Go value.
+func (v *value) convert() (interface{}, error) { //nolint:gocyclo
+	switch {
+	case v.Int != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.Int), 10, 64)
+
+	case v.I4 != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.I4), 10, 64)
+
+	case v.I8 != nil:
+		return strconv.ParseInt(strings.TrimSpace(*v.I8), 10, 64)
+
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+
+	case v.Boolean != nil:
+		switch strings.TrimSpace(*v.Boolean) {
+		case "1":
+			return true, nil
+		case "0":
+			return false, nil
+		}
+
+		return nil, errors.New("xmlrpc: wrong boolean value")
+
+	case v.String != nil:
+		return *v.String, nil
+
+	case v.Base64 != nil:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(*v.Base64))
+
+	case v.DateTime != nil:
+		return parseDateTime(strings.TrimSpace(*v.DateTime))
+
+	case v.Struct != nil:
+		result := make(map[string]interface{}, len(v.Struct.Members))
+
+		for _, m := range v.Struct.Members {
+			converted, err := m.Value.convert()
+			if err != nil {
+				return nil, err
+			}
+
+			result[m.Name] = converted
+		}
+
+		return result, nil
+
+	case v.Array != nil:
+		result := make([]interface{}, 0, len(v.Array.Values))
+
+		for _, item := range v.Array.Values {
+			converted, err := item.convert()
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, converted)
+		}
+
+		return result, nil
+
+	case v.Nil != nil:
+		return nil, nil
+	}
+
+	return v.Text, nil
+}
+
+// parseDateTime parses `dateTime.iso8601` value (some clients send it with dashes and time zone).
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range []string{dateTimeLayout, "2006-01-02T15:04:05", time.RFC3339, "20060102T15:04:05Z07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("xmlrpc: wrong dateTime.iso8601 value")
+}
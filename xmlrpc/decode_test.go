@@ -0,0 +1,146 @@
+package xmlrpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMethodCall(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		giveXML        string
+		wantMethodName string
+		wantParams     interface{}
+		wantErr        string
+	}{
+		{
+			name:           "without params",
+			giveXML:        `<?xml version="1.0"?><methodCall><methodName>ping</methodName></methodCall>`,
+			wantMethodName: "ping",
+		},
+		{
+			name: "scalar params",
+			giveXML: `<?xml version="1.0"?>
+				<methodCall>
+					<methodName> examples.scalars </methodName>
+					<params>
+						<param><value><i4>41</i4></value></param>
+						<param><value><int>-2</int></value></param>
+						<param><value><i8>9000000000</i8></value></param>
+						<param><value><double>-12.214</double></value></param>
+						<param><value><boolean>1</boolean></value></param>
+						<param><value><boolean>0</boolean></value></param>
+						<param><value><string>foo &amp; bar</string></value></param>
+						<param><value>untyped</value></param>
+						<param><value><base64>eW91IGNhbid0IHJlYWQgdGhpcyE=</base64></value></param>
+						<param><value><dateTime.iso8601>19980717T14:08:55</dateTime.iso8601></value></param>
+						<param><value><nil/></value></param>
+					</params>
+				</methodCall>`,
+			wantMethodName: "examples.scalars",
+			wantParams: []interface{}{
+				int64(41), int64(-2), int64(9000000000), -12.214, true, false, "foo & bar", "untyped",
+				[]byte("you can't read this!"), time.Date(1998, 7, 17, 14, 8, 55, 0, time.UTC), nil,
+			},
+		},
+		{
+			name: "single struct param",
+			giveXML: `<methodCall><methodName>user.create</methodName><params><param><value><struct>
+						<member><name>name</name><value><string>John</string></value></member>
+						<member><name>age</name><value><int>33</int></value></member>
+						<member><name>tags</name><value><array><data>
+							<value>a</value>
+							<value><int>1</int></value>
+						</data></array></value></member>
+						<member><name>meta</name><value><struct></struct></value></member>
+					</struct></value></param></params></methodCall>`,
+			wantMethodName: "user.create",
+			wantParams: map[string]interface{}{
+				"name": "John",
+				"age":  int64(33),
+				"tags": []interface{}{"a", int64(1)},
+				"meta": map[string]interface{}{},
+			},
+		},
+		{
+			name: "struct with other params",
+			giveXML: `<methodCall><methodName>foo</methodName><params>
+						<param><value><struct><member><name>a</name><value>b</value></member></struct></value></param>
+						<param><value><array><data></data></array></value></param>
+					</params></methodCall>`,
+			wantMethodName: "foo",
+			wantParams:     []interface{}{map[string]interface{}{"a": "b"}, []interface{}{}},
+		},
+		{
+			name:    "invalid XML",
+			giveXML: `<methodCall><methodName>foo</methodName>`,
+			wantErr: "EOF",
+		},
+		{
+			name:    "wrong root element",
+			giveXML: `<methodResponse></methodResponse>`,
+			wantErr: "expected element type <methodCall>",
+		},
+		{
+			name:    "empty method name",
+			giveXML: `<methodCall><methodName> </methodName></methodCall>`,
+			wantErr: "empty method name",
+		},
+		{
+			name: "wrong int",
+			giveXML: `<methodCall><methodName>foo</methodName><params>
+						<param><value><int>a</int></value></param>
+					</params></methodCall>`,
+			wantErr: "invalid syntax",
+		},
+		{
+			name: "wrong boolean in array",
+			giveXML: `<methodCall><methodName>foo</methodName><params><param><value><array><data>
+						<value><boolean>true</boolean></value>
+					</data></array></value></param></params></methodCall>`,
+			wantErr: "wrong boolean value",
+		},
+		{
+			name: "wrong dateTime in struct",
+			giveXML: `<methodCall><methodName>foo</methodName><params><param><value><struct>
+						<member><name>a</name><value><dateTime.iso8601>yesterday</dateTime.iso8601></value></member>
+					</struct></value></param></params></methodCall>`,
+			wantErr: "wrong dateTime.iso8601 value",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			methodName, params, err := DecodeMethodCall(strings.NewReader(tt.giveXML))
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMethodName, methodName)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	t.Parallel()
+
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, s := range []string{"20200102T03:04:05", "2020-01-02T03:04:05", "2020-01-02T03:04:05Z", "20200102T03:04:05Z"} {
+		got, err := parseDateTime(s)
+
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(got), s)
+	}
+}
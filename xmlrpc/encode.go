@@ -0,0 +1,282 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarampampam/go-jsonrpc"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>`
+
+// EncodeResponse writes `methodResponse` document with passed result. Structures are encoded using the "json" tags
+// (for the members naming), time.Time as `dateTime.iso8601`, []byte as `base64` and nil as `<nil/>` (extension).
+func EncodeResponse(w io.Writer, result interface{}) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(xmlHeader + "<methodResponse><params><param>")
+
+	if err := encodeValue(&buf, reflect.ValueOf(result)); err != nil {
+		return err
+	}
+
+	buf.WriteString("</param></params></methodResponse>")
+
+	_, err := buf.WriteTo(w)
+
+	return err
+}
+
+// EncodeFault writes `methodResponse` document with a fault. Error data (when it is a string) will be appended to
+// the fault string.
+func EncodeFault(w io.Writer, err jsonrpc.Error) error {
+	var faultString = err.GetMessage()
+
+	if data, ok := err.GetData().(string); ok && data != "" {
+		faultString += ": " + data
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(xmlHeader + "<methodResponse><fault>")
+
+	_ = encodeValue(&buf, reflect.ValueOf(map[string]interface{}{
+		"faultCode":   err.GetCode(),
+		"faultString": faultString,
+	}))
+
+	buf.WriteString("</fault></methodResponse>")
+
+	_, writeErr := buf.WriteTo(w)
+
+	return writeErr
+}
+
+var ( //nolint:gochecknoglobals
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+func encodeValue(buf *bytes.Buffer, v reflect.Value) error { //nolint:gocyclo,funlen
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			break
+		}
+
+		v = v.Elem()
+	}
+
+	buf.WriteString("<value>")
+
+	switch {
+	case !v.IsValid() || ((v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()):
+		buf.WriteString("<nil/>")
+
+	case v.Type() == timeType:
+		buf.WriteString("<dateTime.iso8601>" + v.Interface().(time.Time).Format(dateTimeLayout) + "</dateTime.iso8601>")
+
+	case v.Type() == bytesType:
+		buf.WriteString("<base64>" + base64.StdEncoding.EncodeToString(v.Bytes()) + "</base64>")
+
+	default:
+		switch v.Kind() {
+		case reflect.Bool:
+			if v.Bool() {
+				buf.WriteString("<boolean>1</boolean>")
+			} else {
+				buf.WriteString("<boolean>0</boolean>")
+			}
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			writeInt(buf, v.Int())
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if u := v.Uint(); u <= math.MaxInt64 {
+				writeInt(buf, int64(u))
+			} else {
+				buf.WriteString("<double>" + strconv.FormatUint(u, 10) + "</double>")
+			}
+
+		case reflect.Float32, reflect.Float64:
+			buf.WriteString("<double>" + strconv.FormatFloat(v.Float(), 'f', -1, 64) + "</double>")
+
+		case reflect.String:
+			buf.WriteString("<string>")
+			_ = xml.EscapeText(buf, []byte(v.String()))
+			buf.WriteString("</string>")
+
+		case reflect.Slice, reflect.Array:
+			buf.WriteString("<array><data>")
+
+			for i := 0; i < v.Len(); i++ {
+				if err := encodeValue(buf, v.Index(i)); err != nil {
+					return err
+				}
+			}
+
+			buf.WriteString("</data></array>")
+
+		case reflect.Map:
+			if err := encodeMap(buf, v); err != nil {
+				return err
+			}
+
+		case reflect.Struct:
+			if err := encodeStruct(buf, v); err != nil {
+				return err
+			}
+
+		default:
+			return errors.New("xmlrpc: unsupported type " + v.Type().String())
+		}
+	}
+
+	buf.WriteString("</value>")
+
+	return nil
+}
+
+func writeInt(buf *bytes.Buffer, i int64) {
+	if i >= math.MinInt32 && i <= math.MaxInt32 {
+		buf.WriteString("<int>" + strconv.FormatInt(i, 10) + "</int>")
+	} else {
+		buf.WriteString("<i8>" + strconv.FormatInt(i, 10) + "</i8>") // extension
+	}
+}
+
+func writeMember(buf *bytes.Buffer, name string, v reflect.Value) error {
+	buf.WriteString("<member><name>")
+	_ = xml.EscapeText(buf, []byte(name))
+	buf.WriteString("</name>")
+
+	if err := encodeValue(buf, v); err != nil {
+		return err
+	}
+
+	buf.WriteString("</member>")
+
+	return nil
+}
+
+func encodeMap(buf *bytes.Buffer, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return errors.New("xmlrpc: unsupported map key type " + v.Type().Key().String())
+	}
+
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	sort.Strings(keys)
+
+	buf.WriteString("<struct>")
+
+	for _, key := range keys {
+		if err := writeMember(buf, key, v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString("</struct>")
+
+	return nil
+}
+
+func encodeStruct(buf *bytes.Buffer, v reflect.Value) error {
+	buf.WriteString("<struct>")
+
+	if err := encodeStructFields(buf, v); err != nil {
+		return err
+	}
+
+	buf.WriteString("</struct>")
+
+	return nil
+}
+
+// encodeStructFields writes struct fields as members (fields of embedded structs without names are flatten).
+func encodeStructFields(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous { // unexported
+			continue
+		}
+
+		name, opts := field.Name, ""
+
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			parts := strings.SplitN(tag, ",", 2) //nolint:gomnd
+			if parts[0] != "" {
+				name = parts[0]
+			}
+
+			if len(parts) > 1 {
+				opts = parts[1]
+			}
+
+			if field.Anonymous && parts[0] != "" {
+				field.Anonymous = false
+			}
+		}
+
+		fv := v.Field(i)
+
+		if field.Anonymous && reflect.Indirect(fv).Kind() == reflect.Struct {
+			if fv.Kind() == reflect.Ptr && fv.IsNil() {
+				continue
+			}
+
+			if err := encodeStructFields(buf, reflect.Indirect(fv)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" || (strings.Contains(opts, "omitempty") && isEmptyValue(fv)) {
+			continue
+		}
+
+		if err := writeMember(buf, name, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
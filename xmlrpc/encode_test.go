@@ -0,0 +1,134 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+)
+
+type (
+	embedded struct {
+		Embedded string `json:"embedded"`
+	}
+
+	encodableStruct struct {
+		embedded
+		Name     string    `json:"name"`
+		Skipped  string    `json:"-"`
+		Empty    string    `json:"empty,omitempty"`
+		NotEmpty int       `json:"not_empty,omitempty"`
+		Untagged bool      // field name is used
+		When     time.Time `json:"when"`
+		hidden   string
+	}
+)
+
+func TestEncodeResponse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		nilPtr   *encodableStruct
+		emptyIfc interface{}
+	)
+
+	cases := []struct {
+		name      string
+		give      interface{}
+		wantValue string
+		wantErr   bool
+	}{
+		{name: "nil", give: nil, wantValue: "<nil/>"},
+		{name: "nil pointer", give: nilPtr, wantValue: "<nil/>"},
+		{name: "nil interface pointer", give: &emptyIfc, wantValue: "<nil/>"},
+		{name: "true", give: true, wantValue: "<boolean>1</boolean>"},
+		{name: "false", give: false, wantValue: "<boolean>0</boolean>"},
+		{name: "int", give: -42, wantValue: "<int>-42</int>"},
+		{name: "big int", give: int64(9000000000), wantValue: "<i8>9000000000</i8>"},
+		{name: "uint", give: uint8(7), wantValue: "<int>7</int>"},
+		{name: "huge uint", give: uint64(18446744073709551615), wantValue: "<double>18446744073709551615</double>"},
+		{name: "float", give: 1.5, wantValue: "<double>1.5</double>"},
+		{name: "string", give: "a < b", wantValue: "<string>a &lt; b</string>"},
+		{name: "bytes", give: []byte("foo"), wantValue: "<base64>Zm9v</base64>"},
+		{
+			name:      "time",
+			give:      time.Date(1998, 7, 17, 14, 8, 55, 0, time.UTC),
+			wantValue: "<dateTime.iso8601>19980717T14:08:55</dateTime.iso8601>",
+		},
+		{
+			name:      "slice",
+			give:      []interface{}{1, "a"},
+			wantValue: "<array><data><value><int>1</int></value><value><string>a</string></value></data></array>",
+		},
+		{
+			name: "map",
+			give: map[string]int{"b": 2, "a": 1},
+			wantValue: "<struct><member><name>a</name><value><int>1</int></value></member>" +
+				"<member><name>b</name><value><int>2</int></value></member></struct>",
+		},
+		{
+			name: "struct",
+			give: &encodableStruct{
+				embedded: embedded{Embedded: "e"},
+				Name:     "n",
+				Skipped:  "s",
+				Untagged: true,
+				When:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				hidden:   "h",
+			},
+			wantValue: "<struct>" +
+				"<member><name>embedded</name><value><string>e</string></value></member>" +
+				"<member><name>name</name><value><string>n</string></value></member>" +
+				"<member><name>Untagged</name><value><boolean>1</boolean></value></member>" +
+				"<member><name>when</name><value><dateTime.iso8601>20200102T03:04:05</dateTime.iso8601></value></member>" +
+				"</struct>",
+		},
+		{name: "unsupported type", give: make(chan int), wantErr: true},
+		{name: "unsupported map key", give: map[int]int{1: 1}, wantErr: true},
+		{name: "unsupported nested type", give: []interface{}{func() {}}, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := EncodeResponse(&buf, tt.give)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t,
+				xmlHeader+"<methodResponse><params><param><value>"+tt.wantValue+"</value></param></params></methodResponse>",
+				buf.String(),
+			)
+		})
+	}
+}
+
+func TestEncodeFault(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := rpcErrors.New(rpcErrors.InvalidParams)
+	err.Data = "wrong number"
+
+	assert.NoError(t, EncodeFault(&buf, err))
+
+	assert.Equal(t, xmlHeader+"<methodResponse><fault><value><struct>"+
+		"<member><name>faultCode</name><value><int>-32602</int></value></member>"+
+		"<member><name>faultString</name><value><string>Invalid params: wrong number</string></value></member>"+
+		"</struct></value></fault></methodResponse>", buf.String())
+
+	buf.Reset()
+
+	assert.NoError(t, EncodeFault(&buf, rpcErrors.New(rpcErrors.MethodNotFound)))
+	assert.True(t, strings.Contains(buf.String(), "<string>Method not found</string>"))
+}
@@ -0,0 +1,73 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	"github.com/tarampampam/go-jsonrpc/metadata"
+)
+
+// Handler is an HTTP handler, that serves XML-RPC requests using the Router.
+type Handler struct {
+	router jsonrpc.Router
+
+	// MaxBodyBytes limits request body size (zero means "without limit").
+	MaxBodyBytes int64
+}
+
+// NewHandler creates new XML-RPC HTTP handler.
+func NewHandler(router jsonrpc.Router) *Handler {
+	return &Handler{router: router}
+}
+
+// ServeHTTP implements http.Handler interface.
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w, r = metadata.WrapHTTP(w, r)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if handler.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, handler.MaxBodyBytes)
+	}
+
+	var buf bytes.Buffer
+
+	methodName, params, decodeErr := DecodeMethodCall(r.Body)
+
+	if decodeErr != nil {
+		err := rpcErrors.New(rpcErrors.Parse)
+		err.Data = decodeErr.Error()
+
+		_ = EncodeFault(&buf, err)
+	} else if result, err := handler.invoke(r.Context(), methodName, params); err != nil {
+		_ = EncodeFault(&buf, err)
+	} else if encodeErr := EncodeResponse(&buf, result); encodeErr != nil {
+		buf.Reset()
+
+		err := rpcErrors.New(rpcErrors.Internal)
+		err.Data = encodeErr.Error()
+
+		_ = EncodeFault(&buf, err)
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = buf.WriteTo(w)
+}
+
+func (handler *Handler) invoke(ctx context.Context, method string, params interface{}) (interface{}, jsonrpc.Error) {
+	if router, ok := handler.router.(jsonrpc.ContextRouter); ok {
+		return router.InvokeContext(ctx, method, params)
+	}
+
+	return handler.router.Invoke(method, params)
+}
@@ -0,0 +1,154 @@
+package xmlrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tarampampam/go-jsonrpc"
+	rpcErrors "github.com/tarampampam/go-jsonrpc/errors"
+	rpcRouter "github.com/tarampampam/go-jsonrpc/router"
+)
+
+type (
+	sumMethod       struct{}
+	sumMethodParams struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+)
+
+func (*sumMethod) GetParamsType() interface{} { return &sumMethodParams{} }
+func (*sumMethod) GetName() string            { return "math.sum" }
+func (*sumMethod) Handle(params interface{}) (interface{}, jsonrpc.Error) {
+	p := params.(*sumMethodParams)
+
+	return map[string]int{"sum": p.A + p.B}, nil
+}
+
+type (
+	echoMethod struct{}
+)
+
+func (*echoMethod) GetParamsType() interface{} { return nil }
+func (*echoMethod) GetName() string            { return "echo" }
+func (*echoMethod) Handle(_ interface{}) (interface{}, jsonrpc.Error) {
+	return make(chan int), nil // cannot be encoded
+}
+
+type plainRouter struct {
+	jsonrpc.Router
+}
+
+func (plainRouter) Invoke(string, interface{}) (interface{}, jsonrpc.Error) {
+	return nil, rpcErrors.New(rpcErrors.Internal)
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	router := rpcRouter.New()
+	assert.NoError(t, router.RegisterMethods(&sumMethod{}, &echoMethod{}))
+
+	cases := []struct {
+		name         string
+		giveMethod   string
+		giveBody     string
+		giveRouter   jsonrpc.Router
+		giveMaxBytes int64
+		wantCode     int
+		wantContains []string
+	}{
+		{
+			name:       "success",
+			giveMethod: http.MethodPost,
+			giveBody: `<methodCall><methodName>math.sum</methodName><params><param><value><struct>
+						<member><name>a</name><value><int>2</int></value></member>
+						<member><name>b</name><value><int>3</int></value></member>
+					</struct></value></param></params></methodCall>`,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<params><param><value><struct><member><name>sum</name><value><int>5</int>"},
+		},
+		{
+			name:         "unknown method",
+			giveMethod:   http.MethodPost,
+			giveBody:     `<methodCall><methodName>foo</methodName></methodCall>`,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<fault>", "<int>-32601</int>", "Method not found"},
+		},
+		{
+			name:       "invalid params",
+			giveMethod: http.MethodPost,
+			giveBody: `<methodCall><methodName>math.sum</methodName><params><param><value><struct>
+						<member><name>a</name><value>x</value></member>
+					</struct></value></param></params></methodCall>`,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<int>-32602</int>"},
+		},
+		{
+			name:         "parse error",
+			giveMethod:   http.MethodPost,
+			giveBody:     `foo`,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<int>-32700</int>", "Parse error: "},
+		},
+		{
+			name:         "too large body",
+			giveMethod:   http.MethodPost,
+			giveBody:     `<methodCall><methodName>math.sum</methodName></methodCall>`,
+			giveMaxBytes: 10,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<int>-32700</int>"},
+		},
+		{
+			name:         "result encoding error",
+			giveMethod:   http.MethodPost,
+			giveBody:     `<methodCall><methodName>echo</methodName></methodCall>`,
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<int>-32603</int>", "unsupported type"},
+		},
+		{
+			name:         "router without context",
+			giveMethod:   http.MethodPost,
+			giveBody:     `<methodCall><methodName>echo</methodName></methodCall>`,
+			giveRouter:   plainRouter{},
+			wantCode:     http.StatusOK,
+			wantContains: []string{"<int>-32603</int>"},
+		},
+		{
+			name:       "wrong HTTP method",
+			giveMethod: http.MethodGet,
+			wantCode:   http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				req, _ = http.NewRequest(tt.giveMethod, "http://rpc", strings.NewReader(tt.giveBody))
+				rr     = httptest.NewRecorder()
+			)
+
+			handler := NewHandler(router)
+			if tt.giveRouter != nil {
+				handler = NewHandler(tt.giveRouter)
+			}
+
+			handler.MaxBodyBytes = tt.giveMaxBytes
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, "text/xml; charset=utf-8", rr.Header().Get("Content-Type"))
+			}
+
+			for _, s := range tt.wantContains {
+				assert.Contains(t, rr.Body.String(), s)
+			}
+		})
+	}
+}